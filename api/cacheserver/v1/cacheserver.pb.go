@@ -25,44 +25,174 @@ var File_cacheserver_v1_cacheserver_proto protoreflect.FileDescriptor
 
 const file_cacheserver_v1_cacheserver_proto_rawDesc = "" +
 	"\n" +
-	" cacheserver/v1/cacheserver.proto\x12\x0ecacheserver.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1fcacheserver/v1/namespaced.proto\x1a\x1bcacheserver/v1/secret.proto2\xaf\x03\n" +
+	" cacheserver/v1/cacheserver.proto\x12\x0ecacheserver.v1\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1acacheserver/v1/audit.proto\x1a\x19cacheserver/v1/lock.proto\x1a\x1fcacheserver/v1/namespaced.proto\x1a\x1ecacheserver/v1/ratelimit.proto\x1a\x1bcacheserver/v1/secret.proto\x1a\x1acacheserver/v1/watch.proto2\xa3\x14\n" +
 	"\vCacheServer\x12;\n" +
 	"\x03Set\x12\x1a.cacheserver.v1.SetRequest\x1a\x16.google.protobuf.Empty\"\x00\x12;\n" +
 	"\x03Del\x12\x1a.cacheserver.v1.DelRequest\x1a\x16.google.protobuf.Empty\"\x00\x12@\n" +
-	"\x03Get\x12\x1a.cacheserver.v1.GetRequest\x1a\x1b.cacheserver.v1.GetResponse\"\x00\x12G\n" +
+	"\x03Get\x12\x1a.cacheserver.v1.GetRequest\x1a\x1b.cacheserver.v1.GetResponse\"\x00\x12F\n" +
+	"\x05Touch\x12\x1c.cacheserver.v1.TouchRequest\x1a\x1d.cacheserver.v1.TouchResponse\"\x00\x12^\n" +
+	"\rCompareAndSet\x12$.cacheserver.v1.CompareAndSetRequest\x1a%.cacheserver.v1.CompareAndSetResponse\"\x00\x12g\n" +
+	"\x10CompareAndDelete\x12'.cacheserver.v1.CompareAndDeleteRequest\x1a(.cacheserver.v1.CompareAndDeleteResponse\"\x00\x12F\n" +
+	"\x04Incr\x12\x1b.cacheserver.v1.IncrRequest\x1a\x1f.cacheserver.v1.CounterResponse\"\x00\x12J\n" +
+	"\x06IncrBy\x12\x1d.cacheserver.v1.IncrByRequest\x1a\x1f.cacheserver.v1.CounterResponse\"\x00\x12F\n" +
+	"\x04Decr\x12\x1b.cacheserver.v1.DecrRequest\x1a\x1f.cacheserver.v1.CounterResponse\"\x00\x12X\n" +
+	"\vIncrWithCap\x12\".cacheserver.v1.IncrWithCapRequest\x1a#.cacheserver.v1.IncrWithCapResponse\"\x00\x12E\n" +
+	"\x05Watch\x12\x1c.cacheserver.v1.WatchRequest\x1a\x1a.cacheserver.v1.WatchEvent\"\x000\x01\x12C\n" +
+	"\x04HSet\x12\x1b.cacheserver.v1.HSetRequest\x1a\x1c.cacheserver.v1.HSetResponse\"\x00\x12C\n" +
+	"\x04HGet\x12\x1b.cacheserver.v1.HGetRequest\x1a\x1c.cacheserver.v1.HGetResponse\"\x00\x12C\n" +
+	"\x04HDel\x12\x1b.cacheserver.v1.HDelRequest\x1a\x1c.cacheserver.v1.HDelResponse\"\x00\x12L\n" +
+	"\aHGetAll\x12\x1e.cacheserver.v1.HGetAllRequest\x1a\x1f.cacheserver.v1.HGetAllResponse\"\x00\x12C\n" +
+	"\x04ZAdd\x12\x1b.cacheserver.v1.ZAddRequest\x1a\x1c.cacheserver.v1.ZAddResponse\"\x00\x12^\n" +
+	"\rZRangeByScore\x12$.cacheserver.v1.ZRangeByScoreRequest\x1a%.cacheserver.v1.ZRangeByScoreResponse\"\x00\x12F\n" +
+	"\x05ZRank\x12\x1c.cacheserver.v1.ZRankRequest\x1a\x1d.cacheserver.v1.ZRankResponse\"\x00\x12L\n" +
+	"\aZIncrBy\x12\x1e.cacheserver.v1.ZIncrByRequest\x1a\x1f.cacheserver.v1.ZIncrByResponse\"\x00\x12F\n" +
+	"\x05LPush\x12\x1c.cacheserver.v1.LPushRequest\x1a\x1d.cacheserver.v1.LPushResponse\"\x00\x12B\n" +
+	"\x04RPop\x12\x1b.cacheserver.v1.RPopRequest\x1a\x1b.cacheserver.v1.PopResponse\"\x00\x12D\n" +
+	"\x05BRPop\x12\x1c.cacheserver.v1.BRPopRequest\x1a\x1b.cacheserver.v1.PopResponse\"\x00\x12R\n" +
+	"\tRateLimit\x12 .cacheserver.v1.RateLimitRequest\x1a!.cacheserver.v1.RateLimitResponse\"\x00\x12X\n" +
+	"\vAcquireLock\x12\".cacheserver.v1.AcquireLockRequest\x1a#.cacheserver.v1.AcquireLockResponse\"\x00\x12R\n" +
+	"\tRenewLock\x12 .cacheserver.v1.RenewLockRequest\x1a!.cacheserver.v1.RenewLockResponse\"\x00\x12X\n" +
+	"\vReleaseLock\x12\".cacheserver.v1.ReleaseLockRequest\x1a#.cacheserver.v1.ReleaseLockResponse\"\x00\x12G\n" +
 	"\tSetSecret\x12 .cacheserver.v1.SetSecretRequest\x1a\x16.google.protobuf.Empty\"\x00\x12G\n" +
 	"\tDelSecret\x12 .cacheserver.v1.DelSecretRequest\x1a\x16.google.protobuf.Empty\"\x00\x12R\n" +
-	"\tGetSecret\x12 .cacheserver.v1.GetSecretRequest\x1a!.cacheserver.v1.GetSecretResponse\"\x00B#Z!cacheserver/api/cacheserver/v1;v1b\x06proto3"
+	"\tGetSecret\x12 .cacheserver.v1.GetSecretRequest\x1a!.cacheserver.v1.GetSecretResponse\"\x00\x12X\n" +
+	"\vListSecrets\x12\".cacheserver.v1.ListSecretsRequest\x1a#.cacheserver.v1.ListSecretsResponse\"\x00\x12Q\n" +
+	"\x0eUndeleteSecret\x12%.cacheserver.v1.UndeleteSecretRequest\x1a\x16.google.protobuf.Empty\"\x00\x12K\n" +
+	"\vPurgeSecret\x12\".cacheserver.v1.PurgeSecretRequest\x1a\x16.google.protobuf.Empty\"\x00\x12d\n" +
+	"\x0fListAuditEvents\x12&.cacheserver.v1.ListAuditEventsRequest\x1a'.cacheserver.v1.ListAuditEventsResponse\"\x00B#Z!cacheserver/api/cacheserver/v1;v1b\x06proto3"
 
 var file_cacheserver_v1_cacheserver_proto_goTypes = []any{
-	(*SetRequest)(nil),        // 0: cacheserver.v1.SetRequest
-	(*DelRequest)(nil),        // 1: cacheserver.v1.DelRequest
-	(*GetRequest)(nil),        // 2: cacheserver.v1.GetRequest
-	(*SetSecretRequest)(nil),  // 3: cacheserver.v1.SetSecretRequest
-	(*DelSecretRequest)(nil),  // 4: cacheserver.v1.DelSecretRequest
-	(*GetSecretRequest)(nil),  // 5: cacheserver.v1.GetSecretRequest
-	(*emptypb.Empty)(nil),     // 6: google.protobuf.Empty
-	(*GetResponse)(nil),       // 7: cacheserver.v1.GetResponse
-	(*GetSecretResponse)(nil), // 8: cacheserver.v1.GetSecretResponse
+	(*SetRequest)(nil),               // 0: cacheserver.v1.SetRequest
+	(*DelRequest)(nil),               // 1: cacheserver.v1.DelRequest
+	(*GetRequest)(nil),               // 2: cacheserver.v1.GetRequest
+	(*TouchRequest)(nil),             // 3: cacheserver.v1.TouchRequest
+	(*CompareAndSetRequest)(nil),     // 4: cacheserver.v1.CompareAndSetRequest
+	(*CompareAndDeleteRequest)(nil),  // 5: cacheserver.v1.CompareAndDeleteRequest
+	(*IncrRequest)(nil),              // 6: cacheserver.v1.IncrRequest
+	(*IncrByRequest)(nil),            // 7: cacheserver.v1.IncrByRequest
+	(*DecrRequest)(nil),              // 8: cacheserver.v1.DecrRequest
+	(*IncrWithCapRequest)(nil),       // 9: cacheserver.v1.IncrWithCapRequest
+	(*WatchRequest)(nil),             // 10: cacheserver.v1.WatchRequest
+	(*HSetRequest)(nil),              // 11: cacheserver.v1.HSetRequest
+	(*HGetRequest)(nil),              // 12: cacheserver.v1.HGetRequest
+	(*HDelRequest)(nil),              // 13: cacheserver.v1.HDelRequest
+	(*HGetAllRequest)(nil),           // 14: cacheserver.v1.HGetAllRequest
+	(*ZAddRequest)(nil),              // 15: cacheserver.v1.ZAddRequest
+	(*ZRangeByScoreRequest)(nil),     // 16: cacheserver.v1.ZRangeByScoreRequest
+	(*ZRankRequest)(nil),             // 17: cacheserver.v1.ZRankRequest
+	(*ZIncrByRequest)(nil),           // 18: cacheserver.v1.ZIncrByRequest
+	(*LPushRequest)(nil),             // 19: cacheserver.v1.LPushRequest
+	(*RPopRequest)(nil),              // 20: cacheserver.v1.RPopRequest
+	(*BRPopRequest)(nil),             // 21: cacheserver.v1.BRPopRequest
+	(*RateLimitRequest)(nil),         // 22: cacheserver.v1.RateLimitRequest
+	(*AcquireLockRequest)(nil),       // 23: cacheserver.v1.AcquireLockRequest
+	(*RenewLockRequest)(nil),         // 24: cacheserver.v1.RenewLockRequest
+	(*ReleaseLockRequest)(nil),       // 25: cacheserver.v1.ReleaseLockRequest
+	(*SetSecretRequest)(nil),         // 26: cacheserver.v1.SetSecretRequest
+	(*DelSecretRequest)(nil),         // 27: cacheserver.v1.DelSecretRequest
+	(*GetSecretRequest)(nil),         // 28: cacheserver.v1.GetSecretRequest
+	(*ListSecretsRequest)(nil),       // 29: cacheserver.v1.ListSecretsRequest
+	(*UndeleteSecretRequest)(nil),    // 30: cacheserver.v1.UndeleteSecretRequest
+	(*PurgeSecretRequest)(nil),       // 31: cacheserver.v1.PurgeSecretRequest
+	(*ListAuditEventsRequest)(nil),   // 32: cacheserver.v1.ListAuditEventsRequest
+	(*emptypb.Empty)(nil),            // 33: google.protobuf.Empty
+	(*GetResponse)(nil),              // 34: cacheserver.v1.GetResponse
+	(*TouchResponse)(nil),            // 35: cacheserver.v1.TouchResponse
+	(*CompareAndSetResponse)(nil),    // 36: cacheserver.v1.CompareAndSetResponse
+	(*CompareAndDeleteResponse)(nil), // 37: cacheserver.v1.CompareAndDeleteResponse
+	(*CounterResponse)(nil),          // 38: cacheserver.v1.CounterResponse
+	(*IncrWithCapResponse)(nil),      // 39: cacheserver.v1.IncrWithCapResponse
+	(*WatchEvent)(nil),               // 40: cacheserver.v1.WatchEvent
+	(*HSetResponse)(nil),             // 41: cacheserver.v1.HSetResponse
+	(*HGetResponse)(nil),             // 42: cacheserver.v1.HGetResponse
+	(*HDelResponse)(nil),             // 43: cacheserver.v1.HDelResponse
+	(*HGetAllResponse)(nil),          // 44: cacheserver.v1.HGetAllResponse
+	(*ZAddResponse)(nil),             // 45: cacheserver.v1.ZAddResponse
+	(*ZRangeByScoreResponse)(nil),    // 46: cacheserver.v1.ZRangeByScoreResponse
+	(*ZRankResponse)(nil),            // 47: cacheserver.v1.ZRankResponse
+	(*ZIncrByResponse)(nil),          // 48: cacheserver.v1.ZIncrByResponse
+	(*LPushResponse)(nil),            // 49: cacheserver.v1.LPushResponse
+	(*PopResponse)(nil),              // 50: cacheserver.v1.PopResponse
+	(*RateLimitResponse)(nil),        // 51: cacheserver.v1.RateLimitResponse
+	(*AcquireLockResponse)(nil),      // 52: cacheserver.v1.AcquireLockResponse
+	(*RenewLockResponse)(nil),        // 53: cacheserver.v1.RenewLockResponse
+	(*ReleaseLockResponse)(nil),      // 54: cacheserver.v1.ReleaseLockResponse
+	(*GetSecretResponse)(nil),        // 55: cacheserver.v1.GetSecretResponse
+	(*ListSecretsResponse)(nil),      // 56: cacheserver.v1.ListSecretsResponse
+	(*ListAuditEventsResponse)(nil),  // 57: cacheserver.v1.ListAuditEventsResponse
 }
 var file_cacheserver_v1_cacheserver_proto_depIdxs = []int32{
-	0, // 0: cacheserver.v1.CacheServer.Set:input_type -> cacheserver.v1.SetRequest
-	1, // 1: cacheserver.v1.CacheServer.Del:input_type -> cacheserver.v1.DelRequest
-	2, // 2: cacheserver.v1.CacheServer.Get:input_type -> cacheserver.v1.GetRequest
-	3, // 3: cacheserver.v1.CacheServer.SetSecret:input_type -> cacheserver.v1.SetSecretRequest
-	4, // 4: cacheserver.v1.CacheServer.DelSecret:input_type -> cacheserver.v1.DelSecretRequest
-	5, // 5: cacheserver.v1.CacheServer.GetSecret:input_type -> cacheserver.v1.GetSecretRequest
-	6, // 6: cacheserver.v1.CacheServer.Set:output_type -> google.protobuf.Empty
-	6, // 7: cacheserver.v1.CacheServer.Del:output_type -> google.protobuf.Empty
-	7, // 8: cacheserver.v1.CacheServer.Get:output_type -> cacheserver.v1.GetResponse
-	6, // 9: cacheserver.v1.CacheServer.SetSecret:output_type -> google.protobuf.Empty
-	6, // 10: cacheserver.v1.CacheServer.DelSecret:output_type -> google.protobuf.Empty
-	8, // 11: cacheserver.v1.CacheServer.GetSecret:output_type -> cacheserver.v1.GetSecretResponse
-	6, // [6:12] is the sub-list for method output_type
-	0, // [0:6] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	0,  // 0: cacheserver.v1.CacheServer.Set:input_type -> cacheserver.v1.SetRequest
+	1,  // 1: cacheserver.v1.CacheServer.Del:input_type -> cacheserver.v1.DelRequest
+	2,  // 2: cacheserver.v1.CacheServer.Get:input_type -> cacheserver.v1.GetRequest
+	3,  // 3: cacheserver.v1.CacheServer.Touch:input_type -> cacheserver.v1.TouchRequest
+	4,  // 4: cacheserver.v1.CacheServer.CompareAndSet:input_type -> cacheserver.v1.CompareAndSetRequest
+	5,  // 5: cacheserver.v1.CacheServer.CompareAndDelete:input_type -> cacheserver.v1.CompareAndDeleteRequest
+	6,  // 6: cacheserver.v1.CacheServer.Incr:input_type -> cacheserver.v1.IncrRequest
+	7,  // 7: cacheserver.v1.CacheServer.IncrBy:input_type -> cacheserver.v1.IncrByRequest
+	8,  // 8: cacheserver.v1.CacheServer.Decr:input_type -> cacheserver.v1.DecrRequest
+	9,  // 9: cacheserver.v1.CacheServer.IncrWithCap:input_type -> cacheserver.v1.IncrWithCapRequest
+	10, // 10: cacheserver.v1.CacheServer.Watch:input_type -> cacheserver.v1.WatchRequest
+	11, // 11: cacheserver.v1.CacheServer.HSet:input_type -> cacheserver.v1.HSetRequest
+	12, // 12: cacheserver.v1.CacheServer.HGet:input_type -> cacheserver.v1.HGetRequest
+	13, // 13: cacheserver.v1.CacheServer.HDel:input_type -> cacheserver.v1.HDelRequest
+	14, // 14: cacheserver.v1.CacheServer.HGetAll:input_type -> cacheserver.v1.HGetAllRequest
+	15, // 15: cacheserver.v1.CacheServer.ZAdd:input_type -> cacheserver.v1.ZAddRequest
+	16, // 16: cacheserver.v1.CacheServer.ZRangeByScore:input_type -> cacheserver.v1.ZRangeByScoreRequest
+	17, // 17: cacheserver.v1.CacheServer.ZRank:input_type -> cacheserver.v1.ZRankRequest
+	18, // 18: cacheserver.v1.CacheServer.ZIncrBy:input_type -> cacheserver.v1.ZIncrByRequest
+	19, // 19: cacheserver.v1.CacheServer.LPush:input_type -> cacheserver.v1.LPushRequest
+	20, // 20: cacheserver.v1.CacheServer.RPop:input_type -> cacheserver.v1.RPopRequest
+	21, // 21: cacheserver.v1.CacheServer.BRPop:input_type -> cacheserver.v1.BRPopRequest
+	22, // 22: cacheserver.v1.CacheServer.RateLimit:input_type -> cacheserver.v1.RateLimitRequest
+	23, // 23: cacheserver.v1.CacheServer.AcquireLock:input_type -> cacheserver.v1.AcquireLockRequest
+	24, // 24: cacheserver.v1.CacheServer.RenewLock:input_type -> cacheserver.v1.RenewLockRequest
+	25, // 25: cacheserver.v1.CacheServer.ReleaseLock:input_type -> cacheserver.v1.ReleaseLockRequest
+	26, // 26: cacheserver.v1.CacheServer.SetSecret:input_type -> cacheserver.v1.SetSecretRequest
+	27, // 27: cacheserver.v1.CacheServer.DelSecret:input_type -> cacheserver.v1.DelSecretRequest
+	28, // 28: cacheserver.v1.CacheServer.GetSecret:input_type -> cacheserver.v1.GetSecretRequest
+	29, // 29: cacheserver.v1.CacheServer.ListSecrets:input_type -> cacheserver.v1.ListSecretsRequest
+	30, // 30: cacheserver.v1.CacheServer.UndeleteSecret:input_type -> cacheserver.v1.UndeleteSecretRequest
+	31, // 31: cacheserver.v1.CacheServer.PurgeSecret:input_type -> cacheserver.v1.PurgeSecretRequest
+	32, // 32: cacheserver.v1.CacheServer.ListAuditEvents:input_type -> cacheserver.v1.ListAuditEventsRequest
+	33, // 33: cacheserver.v1.CacheServer.Set:output_type -> google.protobuf.Empty
+	33, // 34: cacheserver.v1.CacheServer.Del:output_type -> google.protobuf.Empty
+	34, // 35: cacheserver.v1.CacheServer.Get:output_type -> cacheserver.v1.GetResponse
+	35, // 36: cacheserver.v1.CacheServer.Touch:output_type -> cacheserver.v1.TouchResponse
+	36, // 37: cacheserver.v1.CacheServer.CompareAndSet:output_type -> cacheserver.v1.CompareAndSetResponse
+	37, // 38: cacheserver.v1.CacheServer.CompareAndDelete:output_type -> cacheserver.v1.CompareAndDeleteResponse
+	38, // 39: cacheserver.v1.CacheServer.Incr:output_type -> cacheserver.v1.CounterResponse
+	38, // 40: cacheserver.v1.CacheServer.IncrBy:output_type -> cacheserver.v1.CounterResponse
+	38, // 41: cacheserver.v1.CacheServer.Decr:output_type -> cacheserver.v1.CounterResponse
+	39, // 42: cacheserver.v1.CacheServer.IncrWithCap:output_type -> cacheserver.v1.IncrWithCapResponse
+	40, // 43: cacheserver.v1.CacheServer.Watch:output_type -> cacheserver.v1.WatchEvent
+	41, // 44: cacheserver.v1.CacheServer.HSet:output_type -> cacheserver.v1.HSetResponse
+	42, // 45: cacheserver.v1.CacheServer.HGet:output_type -> cacheserver.v1.HGetResponse
+	43, // 46: cacheserver.v1.CacheServer.HDel:output_type -> cacheserver.v1.HDelResponse
+	44, // 47: cacheserver.v1.CacheServer.HGetAll:output_type -> cacheserver.v1.HGetAllResponse
+	45, // 48: cacheserver.v1.CacheServer.ZAdd:output_type -> cacheserver.v1.ZAddResponse
+	46, // 49: cacheserver.v1.CacheServer.ZRangeByScore:output_type -> cacheserver.v1.ZRangeByScoreResponse
+	47, // 50: cacheserver.v1.CacheServer.ZRank:output_type -> cacheserver.v1.ZRankResponse
+	48, // 51: cacheserver.v1.CacheServer.ZIncrBy:output_type -> cacheserver.v1.ZIncrByResponse
+	49, // 52: cacheserver.v1.CacheServer.LPush:output_type -> cacheserver.v1.LPushResponse
+	50, // 53: cacheserver.v1.CacheServer.RPop:output_type -> cacheserver.v1.PopResponse
+	50, // 54: cacheserver.v1.CacheServer.BRPop:output_type -> cacheserver.v1.PopResponse
+	51, // 55: cacheserver.v1.CacheServer.RateLimit:output_type -> cacheserver.v1.RateLimitResponse
+	52, // 56: cacheserver.v1.CacheServer.AcquireLock:output_type -> cacheserver.v1.AcquireLockResponse
+	53, // 57: cacheserver.v1.CacheServer.RenewLock:output_type -> cacheserver.v1.RenewLockResponse
+	54, // 58: cacheserver.v1.CacheServer.ReleaseLock:output_type -> cacheserver.v1.ReleaseLockResponse
+	33, // 59: cacheserver.v1.CacheServer.SetSecret:output_type -> google.protobuf.Empty
+	33, // 60: cacheserver.v1.CacheServer.DelSecret:output_type -> google.protobuf.Empty
+	55, // 61: cacheserver.v1.CacheServer.GetSecret:output_type -> cacheserver.v1.GetSecretResponse
+	56, // 62: cacheserver.v1.CacheServer.ListSecrets:output_type -> cacheserver.v1.ListSecretsResponse
+	33, // 63: cacheserver.v1.CacheServer.UndeleteSecret:output_type -> google.protobuf.Empty
+	33, // 64: cacheserver.v1.CacheServer.PurgeSecret:output_type -> google.protobuf.Empty
+	57, // 65: cacheserver.v1.CacheServer.ListAuditEvents:output_type -> cacheserver.v1.ListAuditEventsResponse
+	33, // [33:66] is the sub-list for method output_type
+	0,  // [0:33] is the sub-list for method input_type
+	0,  // [0:0] is the sub-list for extension type_name
+	0,  // [0:0] is the sub-list for extension extendee
+	0,  // [0:0] is the sub-list for field type_name
 }
 
 func init() { file_cacheserver_v1_cacheserver_proto_init() }
@@ -70,8 +200,12 @@ func file_cacheserver_v1_cacheserver_proto_init() {
 	if File_cacheserver_v1_cacheserver_proto != nil {
 		return
 	}
+	file_cacheserver_v1_audit_proto_init()
+	file_cacheserver_v1_lock_proto_init()
 	file_cacheserver_v1_namespaced_proto_init()
+	file_cacheserver_v1_ratelimit_proto_init()
 	file_cacheserver_v1_secret_proto_init()
+	file_cacheserver_v1_watch_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
@@ -20,12 +20,39 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	CacheServer_Set_FullMethodName       = "/cacheserver.v1.CacheServer/Set"
-	CacheServer_Del_FullMethodName       = "/cacheserver.v1.CacheServer/Del"
-	CacheServer_Get_FullMethodName       = "/cacheserver.v1.CacheServer/Get"
-	CacheServer_SetSecret_FullMethodName = "/cacheserver.v1.CacheServer/SetSecret"
-	CacheServer_DelSecret_FullMethodName = "/cacheserver.v1.CacheServer/DelSecret"
-	CacheServer_GetSecret_FullMethodName = "/cacheserver.v1.CacheServer/GetSecret"
+	CacheServer_Set_FullMethodName              = "/cacheserver.v1.CacheServer/Set"
+	CacheServer_Del_FullMethodName              = "/cacheserver.v1.CacheServer/Del"
+	CacheServer_Get_FullMethodName              = "/cacheserver.v1.CacheServer/Get"
+	CacheServer_Touch_FullMethodName            = "/cacheserver.v1.CacheServer/Touch"
+	CacheServer_CompareAndSet_FullMethodName    = "/cacheserver.v1.CacheServer/CompareAndSet"
+	CacheServer_CompareAndDelete_FullMethodName = "/cacheserver.v1.CacheServer/CompareAndDelete"
+	CacheServer_Incr_FullMethodName             = "/cacheserver.v1.CacheServer/Incr"
+	CacheServer_IncrBy_FullMethodName           = "/cacheserver.v1.CacheServer/IncrBy"
+	CacheServer_Decr_FullMethodName             = "/cacheserver.v1.CacheServer/Decr"
+	CacheServer_IncrWithCap_FullMethodName      = "/cacheserver.v1.CacheServer/IncrWithCap"
+	CacheServer_Watch_FullMethodName            = "/cacheserver.v1.CacheServer/Watch"
+	CacheServer_HSet_FullMethodName             = "/cacheserver.v1.CacheServer/HSet"
+	CacheServer_HGet_FullMethodName             = "/cacheserver.v1.CacheServer/HGet"
+	CacheServer_HDel_FullMethodName             = "/cacheserver.v1.CacheServer/HDel"
+	CacheServer_HGetAll_FullMethodName          = "/cacheserver.v1.CacheServer/HGetAll"
+	CacheServer_ZAdd_FullMethodName             = "/cacheserver.v1.CacheServer/ZAdd"
+	CacheServer_ZRangeByScore_FullMethodName    = "/cacheserver.v1.CacheServer/ZRangeByScore"
+	CacheServer_ZRank_FullMethodName            = "/cacheserver.v1.CacheServer/ZRank"
+	CacheServer_ZIncrBy_FullMethodName          = "/cacheserver.v1.CacheServer/ZIncrBy"
+	CacheServer_LPush_FullMethodName            = "/cacheserver.v1.CacheServer/LPush"
+	CacheServer_RPop_FullMethodName             = "/cacheserver.v1.CacheServer/RPop"
+	CacheServer_BRPop_FullMethodName            = "/cacheserver.v1.CacheServer/BRPop"
+	CacheServer_RateLimit_FullMethodName        = "/cacheserver.v1.CacheServer/RateLimit"
+	CacheServer_AcquireLock_FullMethodName      = "/cacheserver.v1.CacheServer/AcquireLock"
+	CacheServer_RenewLock_FullMethodName        = "/cacheserver.v1.CacheServer/RenewLock"
+	CacheServer_ReleaseLock_FullMethodName      = "/cacheserver.v1.CacheServer/ReleaseLock"
+	CacheServer_SetSecret_FullMethodName        = "/cacheserver.v1.CacheServer/SetSecret"
+	CacheServer_DelSecret_FullMethodName        = "/cacheserver.v1.CacheServer/DelSecret"
+	CacheServer_GetSecret_FullMethodName        = "/cacheserver.v1.CacheServer/GetSecret"
+	CacheServer_ListSecrets_FullMethodName      = "/cacheserver.v1.CacheServer/ListSecrets"
+	CacheServer_UndeleteSecret_FullMethodName   = "/cacheserver.v1.CacheServer/UndeleteSecret"
+	CacheServer_PurgeSecret_FullMethodName      = "/cacheserver.v1.CacheServer/PurgeSecret"
+	CacheServer_ListAuditEvents_FullMethodName  = "/cacheserver.v1.CacheServer/ListAuditEvents"
 )
 
 // CacheServerClient is the client API for CacheServer service.
@@ -34,10 +61,42 @@ const (
 type CacheServerClient interface {
 	Set(ctx context.Context, in *SetRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Del(ctx context.Context, in *DelRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// Get may be served by a Redis replica when replica reads are enabled.
+	// Reading your own writes is only guaranteed through the instance that
+	// handled them: another instance may return the previous value for the
+	// replication lag, unless the namespace is configured as fresh.
 	Get(ctx context.Context, in *GetRequest, opts ...grpc.CallOption) (*GetResponse, error)
+	Touch(ctx context.Context, in *TouchRequest, opts ...grpc.CallOption) (*TouchResponse, error)
+	CompareAndSet(ctx context.Context, in *CompareAndSetRequest, opts ...grpc.CallOption) (*CompareAndSetResponse, error)
+	CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, opts ...grpc.CallOption) (*CompareAndDeleteResponse, error)
+	Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*CounterResponse, error)
+	IncrBy(ctx context.Context, in *IncrByRequest, opts ...grpc.CallOption) (*CounterResponse, error)
+	Decr(ctx context.Context, in *DecrRequest, opts ...grpc.CallOption) (*CounterResponse, error)
+	IncrWithCap(ctx context.Context, in *IncrWithCapRequest, opts ...grpc.CallOption) (*IncrWithCapResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+	HSet(ctx context.Context, in *HSetRequest, opts ...grpc.CallOption) (*HSetResponse, error)
+	HGet(ctx context.Context, in *HGetRequest, opts ...grpc.CallOption) (*HGetResponse, error)
+	HDel(ctx context.Context, in *HDelRequest, opts ...grpc.CallOption) (*HDelResponse, error)
+	HGetAll(ctx context.Context, in *HGetAllRequest, opts ...grpc.CallOption) (*HGetAllResponse, error)
+	ZAdd(ctx context.Context, in *ZAddRequest, opts ...grpc.CallOption) (*ZAddResponse, error)
+	ZRangeByScore(ctx context.Context, in *ZRangeByScoreRequest, opts ...grpc.CallOption) (*ZRangeByScoreResponse, error)
+	ZRank(ctx context.Context, in *ZRankRequest, opts ...grpc.CallOption) (*ZRankResponse, error)
+	ZIncrBy(ctx context.Context, in *ZIncrByRequest, opts ...grpc.CallOption) (*ZIncrByResponse, error)
+	LPush(ctx context.Context, in *LPushRequest, opts ...grpc.CallOption) (*LPushResponse, error)
+	RPop(ctx context.Context, in *RPopRequest, opts ...grpc.CallOption) (*PopResponse, error)
+	BRPop(ctx context.Context, in *BRPopRequest, opts ...grpc.CallOption) (*PopResponse, error)
+	RateLimit(ctx context.Context, in *RateLimitRequest, opts ...grpc.CallOption) (*RateLimitResponse, error)
+	AcquireLock(ctx context.Context, in *AcquireLockRequest, opts ...grpc.CallOption) (*AcquireLockResponse, error)
+	RenewLock(ctx context.Context, in *RenewLockRequest, opts ...grpc.CallOption) (*RenewLockResponse, error)
+	ReleaseLock(ctx context.Context, in *ReleaseLockRequest, opts ...grpc.CallOption) (*ReleaseLockResponse, error)
 	SetSecret(ctx context.Context, in *SetSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	DelSecret(ctx context.Context, in *DelSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	// GetSecret reads your own writes through the same instance only, like Get.
 	GetSecret(ctx context.Context, in *GetSecretRequest, opts ...grpc.CallOption) (*GetSecretResponse, error)
+	ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error)
+	UndeleteSecret(ctx context.Context, in *UndeleteSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	PurgeSecret(ctx context.Context, in *PurgeSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error)
 }
 
 type cacheServerClient struct {
@@ -78,6 +137,245 @@ func (c *cacheServerClient) Get(ctx context.Context, in *GetRequest, opts ...grp
 	return out, nil
 }
 
+func (c *cacheServerClient) Touch(ctx context.Context, in *TouchRequest, opts ...grpc.CallOption) (*TouchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TouchResponse)
+	err := c.cc.Invoke(ctx, CacheServer_Touch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) CompareAndSet(ctx context.Context, in *CompareAndSetRequest, opts ...grpc.CallOption) (*CompareAndSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareAndSetResponse)
+	err := c.cc.Invoke(ctx, CacheServer_CompareAndSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) CompareAndDelete(ctx context.Context, in *CompareAndDeleteRequest, opts ...grpc.CallOption) (*CompareAndDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompareAndDeleteResponse)
+	err := c.cc.Invoke(ctx, CacheServer_CompareAndDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Incr(ctx context.Context, in *IncrRequest, opts ...grpc.CallOption) (*CounterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CounterResponse)
+	err := c.cc.Invoke(ctx, CacheServer_Incr_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) IncrBy(ctx context.Context, in *IncrByRequest, opts ...grpc.CallOption) (*CounterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CounterResponse)
+	err := c.cc.Invoke(ctx, CacheServer_IncrBy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Decr(ctx context.Context, in *DecrRequest, opts ...grpc.CallOption) (*CounterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CounterResponse)
+	err := c.cc.Invoke(ctx, CacheServer_Decr_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) IncrWithCap(ctx context.Context, in *IncrWithCapRequest, opts ...grpc.CallOption) (*IncrWithCapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IncrWithCapResponse)
+	err := c.cc.Invoke(ctx, CacheServer_IncrWithCap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CacheServer_ServiceDesc.Streams[0], CacheServer_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CacheServer_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+func (c *cacheServerClient) HSet(ctx context.Context, in *HSetRequest, opts ...grpc.CallOption) (*HSetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HSetResponse)
+	err := c.cc.Invoke(ctx, CacheServer_HSet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) HGet(ctx context.Context, in *HGetRequest, opts ...grpc.CallOption) (*HGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HGetResponse)
+	err := c.cc.Invoke(ctx, CacheServer_HGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) HDel(ctx context.Context, in *HDelRequest, opts ...grpc.CallOption) (*HDelResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HDelResponse)
+	err := c.cc.Invoke(ctx, CacheServer_HDel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) HGetAll(ctx context.Context, in *HGetAllRequest, opts ...grpc.CallOption) (*HGetAllResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HGetAllResponse)
+	err := c.cc.Invoke(ctx, CacheServer_HGetAll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ZAdd(ctx context.Context, in *ZAddRequest, opts ...grpc.CallOption) (*ZAddResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZAddResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ZAdd_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ZRangeByScore(ctx context.Context, in *ZRangeByScoreRequest, opts ...grpc.CallOption) (*ZRangeByScoreResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRangeByScoreResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ZRangeByScore_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ZRank(ctx context.Context, in *ZRankRequest, opts ...grpc.CallOption) (*ZRankResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZRankResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ZRank_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ZIncrBy(ctx context.Context, in *ZIncrByRequest, opts ...grpc.CallOption) (*ZIncrByResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ZIncrByResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ZIncrBy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) LPush(ctx context.Context, in *LPushRequest, opts ...grpc.CallOption) (*LPushResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LPushResponse)
+	err := c.cc.Invoke(ctx, CacheServer_LPush_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) RPop(ctx context.Context, in *RPopRequest, opts ...grpc.CallOption) (*PopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PopResponse)
+	err := c.cc.Invoke(ctx, CacheServer_RPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) BRPop(ctx context.Context, in *BRPopRequest, opts ...grpc.CallOption) (*PopResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PopResponse)
+	err := c.cc.Invoke(ctx, CacheServer_BRPop_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) RateLimit(ctx context.Context, in *RateLimitRequest, opts ...grpc.CallOption) (*RateLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RateLimitResponse)
+	err := c.cc.Invoke(ctx, CacheServer_RateLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) AcquireLock(ctx context.Context, in *AcquireLockRequest, opts ...grpc.CallOption) (*AcquireLockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcquireLockResponse)
+	err := c.cc.Invoke(ctx, CacheServer_AcquireLock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) RenewLock(ctx context.Context, in *RenewLockRequest, opts ...grpc.CallOption) (*RenewLockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenewLockResponse)
+	err := c.cc.Invoke(ctx, CacheServer_RenewLock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ReleaseLock(ctx context.Context, in *ReleaseLockRequest, opts ...grpc.CallOption) (*ReleaseLockResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReleaseLockResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ReleaseLock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *cacheServerClient) SetSecret(ctx context.Context, in *SetSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(emptypb.Empty)
@@ -108,16 +406,88 @@ func (c *cacheServerClient) GetSecret(ctx context.Context, in *GetSecretRequest,
 	return out, nil
 }
 
+func (c *cacheServerClient) ListSecrets(ctx context.Context, in *ListSecretsRequest, opts ...grpc.CallOption) (*ListSecretsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListSecretsResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ListSecrets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) UndeleteSecret(ctx context.Context, in *UndeleteSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CacheServer_UndeleteSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) PurgeSecret(ctx context.Context, in *PurgeSecretRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, CacheServer_PurgeSecret_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cacheServerClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsRequest, opts ...grpc.CallOption) (*ListAuditEventsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAuditEventsResponse)
+	err := c.cc.Invoke(ctx, CacheServer_ListAuditEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // CacheServerServer is the server API for CacheServer service.
 // All implementations must embed UnimplementedCacheServerServer
 // for forward compatibility.
 type CacheServerServer interface {
 	Set(context.Context, *SetRequest) (*emptypb.Empty, error)
 	Del(context.Context, *DelRequest) (*emptypb.Empty, error)
+	// Get may be served by a Redis replica when replica reads are enabled.
+	// Reading your own writes is only guaranteed through the instance that
+	// handled them: another instance may return the previous value for the
+	// replication lag, unless the namespace is configured as fresh.
 	Get(context.Context, *GetRequest) (*GetResponse, error)
+	Touch(context.Context, *TouchRequest) (*TouchResponse, error)
+	CompareAndSet(context.Context, *CompareAndSetRequest) (*CompareAndSetResponse, error)
+	CompareAndDelete(context.Context, *CompareAndDeleteRequest) (*CompareAndDeleteResponse, error)
+	Incr(context.Context, *IncrRequest) (*CounterResponse, error)
+	IncrBy(context.Context, *IncrByRequest) (*CounterResponse, error)
+	Decr(context.Context, *DecrRequest) (*CounterResponse, error)
+	IncrWithCap(context.Context, *IncrWithCapRequest) (*IncrWithCapResponse, error)
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	HSet(context.Context, *HSetRequest) (*HSetResponse, error)
+	HGet(context.Context, *HGetRequest) (*HGetResponse, error)
+	HDel(context.Context, *HDelRequest) (*HDelResponse, error)
+	HGetAll(context.Context, *HGetAllRequest) (*HGetAllResponse, error)
+	ZAdd(context.Context, *ZAddRequest) (*ZAddResponse, error)
+	ZRangeByScore(context.Context, *ZRangeByScoreRequest) (*ZRangeByScoreResponse, error)
+	ZRank(context.Context, *ZRankRequest) (*ZRankResponse, error)
+	ZIncrBy(context.Context, *ZIncrByRequest) (*ZIncrByResponse, error)
+	LPush(context.Context, *LPushRequest) (*LPushResponse, error)
+	RPop(context.Context, *RPopRequest) (*PopResponse, error)
+	BRPop(context.Context, *BRPopRequest) (*PopResponse, error)
+	RateLimit(context.Context, *RateLimitRequest) (*RateLimitResponse, error)
+	AcquireLock(context.Context, *AcquireLockRequest) (*AcquireLockResponse, error)
+	RenewLock(context.Context, *RenewLockRequest) (*RenewLockResponse, error)
+	ReleaseLock(context.Context, *ReleaseLockRequest) (*ReleaseLockResponse, error)
 	SetSecret(context.Context, *SetSecretRequest) (*emptypb.Empty, error)
 	DelSecret(context.Context, *DelSecretRequest) (*emptypb.Empty, error)
+	// GetSecret reads your own writes through the same instance only, like Get.
 	GetSecret(context.Context, *GetSecretRequest) (*GetSecretResponse, error)
+	ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error)
+	UndeleteSecret(context.Context, *UndeleteSecretRequest) (*emptypb.Empty, error)
+	PurgeSecret(context.Context, *PurgeSecretRequest) (*emptypb.Empty, error)
+	ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error)
 	mustEmbedUnimplementedCacheServerServer()
 }
 
@@ -137,6 +507,75 @@ func (UnimplementedCacheServerServer) Del(context.Context, *DelRequest) (*emptyp
 func (UnimplementedCacheServerServer) Get(context.Context, *GetRequest) (*GetResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method Get not implemented")
 }
+func (UnimplementedCacheServerServer) Touch(context.Context, *TouchRequest) (*TouchResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Touch not implemented")
+}
+func (UnimplementedCacheServerServer) CompareAndSet(context.Context, *CompareAndSetRequest) (*CompareAndSetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompareAndSet not implemented")
+}
+func (UnimplementedCacheServerServer) CompareAndDelete(context.Context, *CompareAndDeleteRequest) (*CompareAndDeleteResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CompareAndDelete not implemented")
+}
+func (UnimplementedCacheServerServer) Incr(context.Context, *IncrRequest) (*CounterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Incr not implemented")
+}
+func (UnimplementedCacheServerServer) IncrBy(context.Context, *IncrByRequest) (*CounterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IncrBy not implemented")
+}
+func (UnimplementedCacheServerServer) Decr(context.Context, *DecrRequest) (*CounterResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Decr not implemented")
+}
+func (UnimplementedCacheServerServer) IncrWithCap(context.Context, *IncrWithCapRequest) (*IncrWithCapResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method IncrWithCap not implemented")
+}
+func (UnimplementedCacheServerServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Error(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedCacheServerServer) HSet(context.Context, *HSetRequest) (*HSetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HSet not implemented")
+}
+func (UnimplementedCacheServerServer) HGet(context.Context, *HGetRequest) (*HGetResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HGet not implemented")
+}
+func (UnimplementedCacheServerServer) HDel(context.Context, *HDelRequest) (*HDelResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HDel not implemented")
+}
+func (UnimplementedCacheServerServer) HGetAll(context.Context, *HGetAllRequest) (*HGetAllResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method HGetAll not implemented")
+}
+func (UnimplementedCacheServerServer) ZAdd(context.Context, *ZAddRequest) (*ZAddResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ZAdd not implemented")
+}
+func (UnimplementedCacheServerServer) ZRangeByScore(context.Context, *ZRangeByScoreRequest) (*ZRangeByScoreResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ZRangeByScore not implemented")
+}
+func (UnimplementedCacheServerServer) ZRank(context.Context, *ZRankRequest) (*ZRankResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ZRank not implemented")
+}
+func (UnimplementedCacheServerServer) ZIncrBy(context.Context, *ZIncrByRequest) (*ZIncrByResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ZIncrBy not implemented")
+}
+func (UnimplementedCacheServerServer) LPush(context.Context, *LPushRequest) (*LPushResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method LPush not implemented")
+}
+func (UnimplementedCacheServerServer) RPop(context.Context, *RPopRequest) (*PopResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RPop not implemented")
+}
+func (UnimplementedCacheServerServer) BRPop(context.Context, *BRPopRequest) (*PopResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method BRPop not implemented")
+}
+func (UnimplementedCacheServerServer) RateLimit(context.Context, *RateLimitRequest) (*RateLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RateLimit not implemented")
+}
+func (UnimplementedCacheServerServer) AcquireLock(context.Context, *AcquireLockRequest) (*AcquireLockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcquireLock not implemented")
+}
+func (UnimplementedCacheServerServer) RenewLock(context.Context, *RenewLockRequest) (*RenewLockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RenewLock not implemented")
+}
+func (UnimplementedCacheServerServer) ReleaseLock(context.Context, *ReleaseLockRequest) (*ReleaseLockResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReleaseLock not implemented")
+}
 func (UnimplementedCacheServerServer) SetSecret(context.Context, *SetSecretRequest) (*emptypb.Empty, error) {
 	return nil, status.Error(codes.Unimplemented, "method SetSecret not implemented")
 }
@@ -146,6 +585,18 @@ func (UnimplementedCacheServerServer) DelSecret(context.Context, *DelSecretReque
 func (UnimplementedCacheServerServer) GetSecret(context.Context, *GetSecretRequest) (*GetSecretResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetSecret not implemented")
 }
+func (UnimplementedCacheServerServer) ListSecrets(context.Context, *ListSecretsRequest) (*ListSecretsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListSecrets not implemented")
+}
+func (UnimplementedCacheServerServer) UndeleteSecret(context.Context, *UndeleteSecretRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method UndeleteSecret not implemented")
+}
+func (UnimplementedCacheServerServer) PurgeSecret(context.Context, *PurgeSecretRequest) (*emptypb.Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method PurgeSecret not implemented")
+}
+func (UnimplementedCacheServerServer) ListAuditEvents(context.Context, *ListAuditEventsRequest) (*ListAuditEventsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListAuditEvents not implemented")
+}
 func (UnimplementedCacheServerServer) mustEmbedUnimplementedCacheServerServer() {}
 func (UnimplementedCacheServerServer) testEmbeddedByValue()                     {}
 
@@ -221,79 +672,646 @@ func _CacheServer_Get_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CacheServer_SetSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetSecretRequest)
+func _CacheServer_Touch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TouchRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CacheServerServer).SetSecret(ctx, in)
+		return srv.(CacheServerServer).Touch(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CacheServer_SetSecret_FullMethodName,
+		FullMethod: CacheServer_Touch_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CacheServerServer).SetSecret(ctx, req.(*SetSecretRequest))
+		return srv.(CacheServerServer).Touch(ctx, req.(*TouchRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CacheServer_DelSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DelSecretRequest)
+func _CacheServer_CompareAndSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndSetRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CacheServerServer).DelSecret(ctx, in)
+		return srv.(CacheServerServer).CompareAndSet(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CacheServer_DelSecret_FullMethodName,
+		FullMethod: CacheServer_CompareAndSet_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CacheServerServer).DelSecret(ctx, req.(*DelSecretRequest))
+		return srv.(CacheServerServer).CompareAndSet(ctx, req.(*CompareAndSetRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _CacheServer_GetSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetSecretRequest)
+func _CacheServer_CompareAndDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompareAndDeleteRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(CacheServerServer).GetSecret(ctx, in)
+		return srv.(CacheServerServer).CompareAndDelete(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: CacheServer_GetSecret_FullMethodName,
+		FullMethod: CacheServer_CompareAndDelete_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(CacheServerServer).GetSecret(ctx, req.(*GetSecretRequest))
+		return srv.(CacheServerServer).CompareAndDelete(ctx, req.(*CompareAndDeleteRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// CacheServer_ServiceDesc is the grpc.ServiceDesc for CacheServer service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var CacheServer_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "cacheserver.v1.CacheServer",
-	HandlerType: (*CacheServerServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Set",
-			Handler:    _CacheServer_Set_Handler,
-		},
-		{
-			MethodName: "Del",
-			Handler:    _CacheServer_Del_Handler,
-		},
-		{
-			MethodName: "Get",
-			Handler:    _CacheServer_Get_Handler,
-		},
+func _CacheServer_Incr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).Incr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_Incr_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).Incr(ctx, req.(*IncrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_IncrBy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrByRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).IncrBy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_IncrBy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).IncrBy(ctx, req.(*IncrByRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_Decr_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecrRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).Decr(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_Decr_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).Decr(ctx, req.(*DecrRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_IncrWithCap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IncrWithCapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).IncrWithCap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_IncrWithCap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).IncrWithCap(ctx, req.(*IncrWithCapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CacheServerServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CacheServer_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+func _CacheServer_HSet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HSetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).HSet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_HSet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).HSet(ctx, req.(*HSetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_HGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).HGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_HGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).HGet(ctx, req.(*HGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_HDel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HDelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).HDel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_HDel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).HDel(ctx, req.(*HDelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_HGetAll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HGetAllRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).HGetAll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_HGetAll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).HGetAll(ctx, req.(*HGetAllRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ZAdd_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZAddRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ZAdd(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ZAdd_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ZAdd(ctx, req.(*ZAddRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ZRangeByScore_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRangeByScoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ZRangeByScore(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ZRangeByScore_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ZRangeByScore(ctx, req.(*ZRangeByScoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ZRank_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZRankRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ZRank(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ZRank_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ZRank(ctx, req.(*ZRankRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ZIncrBy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ZIncrByRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ZIncrBy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ZIncrBy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ZIncrBy(ctx, req.(*ZIncrByRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_LPush_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LPushRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).LPush(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_LPush_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).LPush(ctx, req.(*LPushRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_RPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RPopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).RPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_RPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).RPop(ctx, req.(*RPopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_BRPop_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BRPopRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).BRPop(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_BRPop_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).BRPop(ctx, req.(*BRPopRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_RateLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RateLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).RateLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_RateLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).RateLimit(ctx, req.(*RateLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_AcquireLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcquireLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).AcquireLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_AcquireLock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).AcquireLock(ctx, req.(*AcquireLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_RenewLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).RenewLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_RenewLock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).RenewLock(ctx, req.(*RenewLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ReleaseLock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReleaseLockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ReleaseLock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ReleaseLock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ReleaseLock(ctx, req.(*ReleaseLockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_SetSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).SetSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_SetSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).SetSecret(ctx, req.(*SetSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_DelSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DelSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).DelSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_DelSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).DelSecret(ctx, req.(*DelSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_GetSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).GetSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_GetSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).GetSecret(ctx, req.(*GetSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ListSecrets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSecretsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ListSecrets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ListSecrets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ListSecrets(ctx, req.(*ListSecretsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_UndeleteSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndeleteSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).UndeleteSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_UndeleteSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).UndeleteSecret(ctx, req.(*UndeleteSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_PurgeSecret_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeSecretRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).PurgeSecret(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_PurgeSecret_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).PurgeSecret(ctx, req.(*PurgeSecretRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CacheServer_ListAuditEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAuditEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CacheServerServer).ListAuditEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CacheServer_ListAuditEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CacheServerServer).ListAuditEvents(ctx, req.(*ListAuditEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CacheServer_ServiceDesc is the grpc.ServiceDesc for CacheServer service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CacheServer_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cacheserver.v1.CacheServer",
+	HandlerType: (*CacheServerServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Set",
+			Handler:    _CacheServer_Set_Handler,
+		},
+		{
+			MethodName: "Del",
+			Handler:    _CacheServer_Del_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _CacheServer_Get_Handler,
+		},
+		{
+			MethodName: "Touch",
+			Handler:    _CacheServer_Touch_Handler,
+		},
+		{
+			MethodName: "CompareAndSet",
+			Handler:    _CacheServer_CompareAndSet_Handler,
+		},
+		{
+			MethodName: "CompareAndDelete",
+			Handler:    _CacheServer_CompareAndDelete_Handler,
+		},
+		{
+			MethodName: "Incr",
+			Handler:    _CacheServer_Incr_Handler,
+		},
+		{
+			MethodName: "IncrBy",
+			Handler:    _CacheServer_IncrBy_Handler,
+		},
+		{
+			MethodName: "Decr",
+			Handler:    _CacheServer_Decr_Handler,
+		},
+		{
+			MethodName: "IncrWithCap",
+			Handler:    _CacheServer_IncrWithCap_Handler,
+		},
+		{
+			MethodName: "HSet",
+			Handler:    _CacheServer_HSet_Handler,
+		},
+		{
+			MethodName: "HGet",
+			Handler:    _CacheServer_HGet_Handler,
+		},
+		{
+			MethodName: "HDel",
+			Handler:    _CacheServer_HDel_Handler,
+		},
+		{
+			MethodName: "HGetAll",
+			Handler:    _CacheServer_HGetAll_Handler,
+		},
+		{
+			MethodName: "ZAdd",
+			Handler:    _CacheServer_ZAdd_Handler,
+		},
+		{
+			MethodName: "ZRangeByScore",
+			Handler:    _CacheServer_ZRangeByScore_Handler,
+		},
+		{
+			MethodName: "ZRank",
+			Handler:    _CacheServer_ZRank_Handler,
+		},
+		{
+			MethodName: "ZIncrBy",
+			Handler:    _CacheServer_ZIncrBy_Handler,
+		},
+		{
+			MethodName: "LPush",
+			Handler:    _CacheServer_LPush_Handler,
+		},
+		{
+			MethodName: "RPop",
+			Handler:    _CacheServer_RPop_Handler,
+		},
+		{
+			MethodName: "BRPop",
+			Handler:    _CacheServer_BRPop_Handler,
+		},
+		{
+			MethodName: "RateLimit",
+			Handler:    _CacheServer_RateLimit_Handler,
+		},
+		{
+			MethodName: "AcquireLock",
+			Handler:    _CacheServer_AcquireLock_Handler,
+		},
+		{
+			MethodName: "RenewLock",
+			Handler:    _CacheServer_RenewLock_Handler,
+		},
+		{
+			MethodName: "ReleaseLock",
+			Handler:    _CacheServer_ReleaseLock_Handler,
+		},
 		{
 			MethodName: "SetSecret",
 			Handler:    _CacheServer_SetSecret_Handler,
@@ -306,7 +1324,29 @@ var CacheServer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetSecret",
 			Handler:    _CacheServer_GetSecret_Handler,
 		},
+		{
+			MethodName: "ListSecrets",
+			Handler:    _CacheServer_ListSecrets_Handler,
+		},
+		{
+			MethodName: "UndeleteSecret",
+			Handler:    _CacheServer_UndeleteSecret_Handler,
+		},
+		{
+			MethodName: "PurgeSecret",
+			Handler:    _CacheServer_PurgeSecret_Handler,
+		},
+		{
+			MethodName: "ListAuditEvents",
+			Handler:    _CacheServer_ListAuditEvents_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _CacheServer_Watch_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "cacheserver/v1/cacheserver.proto",
 }
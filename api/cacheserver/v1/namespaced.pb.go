@@ -144,9 +144,12 @@ func (x *DelRequest) GetKey() string {
 }
 
 type GetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
-	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// slidingExpire, when set, resets the TTL of the key to this duration on
+	// every read, so it expires after that long without access.
+	SlidingExpire *durationpb.Duration `protobuf:"bytes,3,opt,name=slidingExpire,proto3,oneof" json:"slidingExpire,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -195,10 +198,22 @@ func (x *GetRequest) GetKey() string {
 	return ""
 }
 
+func (x *GetRequest) GetSlidingExpire() *durationpb.Duration {
+	if x != nil {
+		return x.SlidingExpire
+	}
+	return nil
+}
+
 type GetResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Value         *anypb.Any             `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Expire        *durationpb.Duration   `protobuf:"bytes,2,opt,name=expire,proto3" json:"expire,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Value  *anypb.Any             `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Expire *durationpb.Duration   `protobuf:"bytes,2,opt,name=expire,proto3" json:"expire,omitempty"`
+	// version changes on every write and is used by CompareAndSet/CompareAndDelete.
+	Version int64 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// stale is set when the value has expired but is served because Redis is
+	// unavailable.
+	Stale         bool `protobuf:"varint,4,opt,name=stale,proto3" json:"stale,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -247,29 +262,2121 @@ func (x *GetResponse) GetExpire() *durationpb.Duration {
 	return nil
 }
 
-var File_cacheserver_v1_namespaced_proto protoreflect.FileDescriptor
+func (x *GetResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
 
-const file_cacheserver_v1_namespaced_proto_rawDesc = "" +
-	"\n" +
-	"\x1fcacheserver/v1/namespaced.proto\x12\x0ecacheserver.v1\x1a\x19google/protobuf/any.proto\x1a\x1egoogle/protobuf/duration.proto\"\xab\x01\n" +
-	"\n" +
-	"SetRequest\x12\x1c\n" +
-	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\x12*\n" +
-	"\x05value\x18\x03 \x01(\v2\x14.google.protobuf.AnyR\x05value\x126\n" +
-	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
-	"\a_expire\"<\n" +
-	"\n" +
-	"DelRequest\x12\x1c\n" +
-	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\"<\n" +
-	"\n" +
-	"GetRequest\x12\x1c\n" +
-	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
-	"\x03key\x18\x02 \x01(\tR\x03key\"l\n" +
-	"\vGetResponse\x12*\n" +
-	"\x05value\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x05value\x121\n" +
-	"\x06expire\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06expireB#Z!cacheserver/api/cacheserver/v1;v1b\x06proto3"
+func (x *GetResponse) GetStale() bool {
+	if x != nil {
+		return x.Stale
+	}
+	return false
+}
+
+type CompareAndSetRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value     *anypb.Any             `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Expire    *durationpb.Duration   `protobuf:"bytes,4,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	// version is the version returned by Get; 0 means the key must not exist.
+	Version       int64 `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareAndSetRequest) Reset() {
+	*x = CompareAndSetRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndSetRequest) ProtoMessage() {}
+
+func (x *CompareAndSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndSetRequest.ProtoReflect.Descriptor instead.
+func (*CompareAndSetRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CompareAndSetRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CompareAndSetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CompareAndSetRequest) GetValue() *anypb.Any {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *CompareAndSetRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+func (x *CompareAndSetRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CompareAndSetResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Succeeded bool                   `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	// version is the new version when the swap succeeded.
+	Version       int64 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareAndSetResponse) Reset() {
+	*x = CompareAndSetResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndSetResponse) ProtoMessage() {}
+
+func (x *CompareAndSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndSetResponse.ProtoReflect.Descriptor instead.
+func (*CompareAndSetResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CompareAndSetResponse) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+func (x *CompareAndSetResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CompareAndDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Version       int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareAndDeleteRequest) Reset() {
+	*x = CompareAndDeleteRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndDeleteRequest) ProtoMessage() {}
+
+func (x *CompareAndDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndDeleteRequest.ProtoReflect.Descriptor instead.
+func (*CompareAndDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CompareAndDeleteRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *CompareAndDeleteRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *CompareAndDeleteRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CompareAndDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Succeeded     bool                   `protobuf:"varint,1,opt,name=succeeded,proto3" json:"succeeded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompareAndDeleteResponse) Reset() {
+	*x = CompareAndDeleteResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompareAndDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompareAndDeleteResponse) ProtoMessage() {}
+
+func (x *CompareAndDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompareAndDeleteResponse.ProtoReflect.Descriptor instead.
+func (*CompareAndDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *CompareAndDeleteResponse) GetSucceeded() bool {
+	if x != nil {
+		return x.Succeeded
+	}
+	return false
+}
+
+// Counter requests. expire is applied only when the counter has no TTL yet,
+// i.e. when it is created.
+type IncrRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,3,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrRequest) Reset() {
+	*x = IncrRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrRequest) ProtoMessage() {}
+
+func (x *IncrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrRequest.ProtoReflect.Descriptor instead.
+func (*IncrRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *IncrRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *IncrRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IncrRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type IncrByRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,4,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrByRequest) Reset() {
+	*x = IncrByRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrByRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrByRequest) ProtoMessage() {}
+
+func (x *IncrByRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrByRequest.ProtoReflect.Descriptor instead.
+func (*IncrByRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *IncrByRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *IncrByRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IncrByRequest) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *IncrByRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type DecrRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,3,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DecrRequest) Reset() {
+	*x = DecrRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DecrRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecrRequest) ProtoMessage() {}
+
+func (x *DecrRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecrRequest.ProtoReflect.Descriptor instead.
+func (*DecrRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DecrRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *DecrRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *DecrRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type CounterResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         int64                  `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CounterResponse) Reset() {
+	*x = CounterResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CounterResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CounterResponse) ProtoMessage() {}
+
+func (x *CounterResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CounterResponse.ProtoReflect.Descriptor instead.
+func (*CounterResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *CounterResponse) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+// IncrWithCapRequest increments a counter only if the result does not exceed cap.
+type IncrWithCapRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Delta         int64                  `protobuf:"varint,3,opt,name=delta,proto3" json:"delta,omitempty"`
+	Cap           int64                  `protobuf:"varint,4,opt,name=cap,proto3" json:"cap,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,5,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrWithCapRequest) Reset() {
+	*x = IncrWithCapRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrWithCapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrWithCapRequest) ProtoMessage() {}
+
+func (x *IncrWithCapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrWithCapRequest.ProtoReflect.Descriptor instead.
+func (*IncrWithCapRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *IncrWithCapRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *IncrWithCapRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *IncrWithCapRequest) GetDelta() int64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *IncrWithCapRequest) GetCap() int64 {
+	if x != nil {
+		return x.Cap
+	}
+	return 0
+}
+
+func (x *IncrWithCapRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type IncrWithCapResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// value is the counter value after the call; unchanged when not applied.
+	Value         int64 `protobuf:"varint,1,opt,name=value,proto3" json:"value,omitempty"`
+	Applied       bool  `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IncrWithCapResponse) Reset() {
+	*x = IncrWithCapResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IncrWithCapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IncrWithCapResponse) ProtoMessage() {}
+
+func (x *IncrWithCapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IncrWithCapResponse.ProtoReflect.Descriptor instead.
+func (*IncrWithCapResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *IncrWithCapResponse) GetValue() int64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *IncrWithCapResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+// Hash requests operate on individual fields of a namespaced key.
+type HSetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        map[string]*anypb.Any  `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,4,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HSetRequest) Reset() {
+	*x = HSetRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HSetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HSetRequest) ProtoMessage() {}
+
+func (x *HSetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HSetRequest.ProtoReflect.Descriptor instead.
+func (*HSetRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *HSetRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HSetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HSetRequest) GetFields() map[string]*anypb.Any {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+func (x *HSetRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type HSetResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// added is the number of fields that did not exist before.
+	Added         int64 `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HSetResponse) Reset() {
+	*x = HSetResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HSetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HSetResponse) ProtoMessage() {}
+
+func (x *HSetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HSetResponse.ProtoReflect.Descriptor instead.
+func (*HSetResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *HSetResponse) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+type HGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Field         string                 `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetRequest) Reset() {
+	*x = HGetRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetRequest) ProtoMessage() {}
+
+func (x *HGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetRequest.ProtoReflect.Descriptor instead.
+func (*HGetRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *HGetRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HGetRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HGetRequest) GetField() string {
+	if x != nil {
+		return x.Field
+	}
+	return ""
+}
+
+type HGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         *anypb.Any             `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetResponse) Reset() {
+	*x = HGetResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetResponse) ProtoMessage() {}
+
+func (x *HGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetResponse.ProtoReflect.Descriptor instead.
+func (*HGetResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *HGetResponse) GetValue() *anypb.Any {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *HGetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type HDelRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Fields        []string               `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HDelRequest) Reset() {
+	*x = HDelRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HDelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HDelRequest) ProtoMessage() {}
+
+func (x *HDelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HDelRequest.ProtoReflect.Descriptor instead.
+func (*HDelRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *HDelRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HDelRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HDelRequest) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type HDelResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       int64                  `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HDelResponse) Reset() {
+	*x = HDelResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HDelResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HDelResponse) ProtoMessage() {}
+
+func (x *HDelResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HDelResponse.ProtoReflect.Descriptor instead.
+func (*HDelResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *HDelResponse) GetDeleted() int64 {
+	if x != nil {
+		return x.Deleted
+	}
+	return 0
+}
+
+type HGetAllRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetAllRequest) Reset() {
+	*x = HGetAllRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetAllRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetAllRequest) ProtoMessage() {}
+
+func (x *HGetAllRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetAllRequest.ProtoReflect.Descriptor instead.
+func (*HGetAllRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HGetAllRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *HGetAllRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type HGetAllResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Fields        map[string]*anypb.Any  `protobuf:"bytes,1,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HGetAllResponse) Reset() {
+	*x = HGetAllResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HGetAllResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HGetAllResponse) ProtoMessage() {}
+
+func (x *HGetAllResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HGetAllResponse.ProtoReflect.Descriptor instead.
+func (*HGetAllResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *HGetAllResponse) GetFields() map[string]*anypb.Any {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+// Sorted set and list requests operate on Redis directly and bypass the
+// local cache level.
+type ZMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        string                 `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	Score         float64                `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZMember) Reset() {
+	*x = ZMember{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZMember) ProtoMessage() {}
+
+func (x *ZMember) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZMember.ProtoReflect.Descriptor instead.
+func (*ZMember) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ZMember) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ZMember) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type ZAddRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Members       []*ZMember             `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,4,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZAddRequest) Reset() {
+	*x = ZAddRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZAddRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZAddRequest) ProtoMessage() {}
+
+func (x *ZAddRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZAddRequest.ProtoReflect.Descriptor instead.
+func (*ZAddRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ZAddRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ZAddRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZAddRequest) GetMembers() []*ZMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *ZAddRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type ZAddResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// added is the number of members that did not exist before.
+	Added         int64 `protobuf:"varint,1,opt,name=added,proto3" json:"added,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZAddResponse) Reset() {
+	*x = ZAddResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZAddResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZAddResponse) ProtoMessage() {}
+
+func (x *ZAddResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZAddResponse.ProtoReflect.Descriptor instead.
+func (*ZAddResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ZAddResponse) GetAdded() int64 {
+	if x != nil {
+		return x.Added
+	}
+	return 0
+}
+
+type ZRangeByScoreRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// min and max use the Redis score range syntax, e.g. "-inf", "(1.5" or
+	// "+inf". Empty means unbounded.
+	Min    string `protobuf:"bytes,3,opt,name=min,proto3" json:"min,omitempty"`
+	Max    string `protobuf:"bytes,4,opt,name=max,proto3" json:"max,omitempty"`
+	Offset int64  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	// count limits the number of members returned, 0 meaning no limit.
+	Count         int64 `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRangeByScoreRequest) Reset() {
+	*x = ZRangeByScoreRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRangeByScoreRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRangeByScoreRequest) ProtoMessage() {}
+
+func (x *ZRangeByScoreRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRangeByScoreRequest.ProtoReflect.Descriptor instead.
+func (*ZRangeByScoreRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ZRangeByScoreRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ZRangeByScoreRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRangeByScoreRequest) GetMin() string {
+	if x != nil {
+		return x.Min
+	}
+	return ""
+}
+
+func (x *ZRangeByScoreRequest) GetMax() string {
+	if x != nil {
+		return x.Max
+	}
+	return ""
+}
+
+func (x *ZRangeByScoreRequest) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *ZRangeByScoreRequest) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type ZRangeByScoreResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*ZMember             `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRangeByScoreResponse) Reset() {
+	*x = ZRangeByScoreResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRangeByScoreResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRangeByScoreResponse) ProtoMessage() {}
+
+func (x *ZRangeByScoreResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRangeByScoreResponse.ProtoReflect.Descriptor instead.
+func (*ZRangeByScoreResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ZRangeByScoreResponse) GetMembers() []*ZMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type ZRankRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Member        string                 `protobuf:"bytes,3,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRankRequest) Reset() {
+	*x = ZRankRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRankRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRankRequest) ProtoMessage() {}
+
+func (x *ZRankRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRankRequest.ProtoReflect.Descriptor instead.
+func (*ZRankRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ZRankRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ZRankRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZRankRequest) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+type ZRankResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rank          int64                  `protobuf:"varint,1,opt,name=rank,proto3" json:"rank,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZRankResponse) Reset() {
+	*x = ZRankResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZRankResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZRankResponse) ProtoMessage() {}
+
+func (x *ZRankResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZRankResponse.ProtoReflect.Descriptor instead.
+func (*ZRankResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ZRankResponse) GetRank() int64 {
+	if x != nil {
+		return x.Rank
+	}
+	return 0
+}
+
+func (x *ZRankResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type ZIncrByRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Member        string                 `protobuf:"bytes,3,opt,name=member,proto3" json:"member,omitempty"`
+	Delta         float64                `protobuf:"fixed64,4,opt,name=delta,proto3" json:"delta,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,5,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZIncrByRequest) Reset() {
+	*x = ZIncrByRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZIncrByRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZIncrByRequest) ProtoMessage() {}
+
+func (x *ZIncrByRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZIncrByRequest.ProtoReflect.Descriptor instead.
+func (*ZIncrByRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ZIncrByRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *ZIncrByRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ZIncrByRequest) GetMember() string {
+	if x != nil {
+		return x.Member
+	}
+	return ""
+}
+
+func (x *ZIncrByRequest) GetDelta() float64 {
+	if x != nil {
+		return x.Delta
+	}
+	return 0
+}
+
+func (x *ZIncrByRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type ZIncrByResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Score         float64                `protobuf:"fixed64,1,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ZIncrByResponse) Reset() {
+	*x = ZIncrByResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ZIncrByResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ZIncrByResponse) ProtoMessage() {}
+
+func (x *ZIncrByResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ZIncrByResponse.ProtoReflect.Descriptor instead.
+func (*ZIncrByResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ZIncrByResponse) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type LPushRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Values        []*anypb.Any           `protobuf:"bytes,3,rep,name=values,proto3" json:"values,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,4,opt,name=expire,proto3,oneof" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LPushRequest) Reset() {
+	*x = LPushRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LPushRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LPushRequest) ProtoMessage() {}
+
+func (x *LPushRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LPushRequest.ProtoReflect.Descriptor instead.
+func (*LPushRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *LPushRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *LPushRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LPushRequest) GetValues() []*anypb.Any {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+func (x *LPushRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type LPushResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// length is the length of the list after the push.
+	Length        int64 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LPushResponse) Reset() {
+	*x = LPushResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LPushResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LPushResponse) ProtoMessage() {}
+
+func (x *LPushResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LPushResponse.ProtoReflect.Descriptor instead.
+func (*LPushResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *LPushResponse) GetLength() int64 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+type RPopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RPopRequest) Reset() {
+	*x = RPopRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RPopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RPopRequest) ProtoMessage() {}
+
+func (x *RPopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RPopRequest.ProtoReflect.Descriptor instead.
+func (*RPopRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *RPopRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *RPopRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type BRPopRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	// timeout is how long to wait for a value, bounded by the request deadline.
+	// Redis waits in whole seconds, so it is truncated to at least one second.
+	Timeout       *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BRPopRequest) Reset() {
+	*x = BRPopRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BRPopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BRPopRequest) ProtoMessage() {}
+
+func (x *BRPopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BRPopRequest.ProtoReflect.Descriptor instead.
+func (*BRPopRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *BRPopRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *BRPopRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *BRPopRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type PopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         *anypb.Any             `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PopResponse) Reset() {
+	*x = PopResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PopResponse) ProtoMessage() {}
+
+func (x *PopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PopResponse.ProtoReflect.Descriptor instead.
+func (*PopResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *PopResponse) GetValue() *anypb.Any {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *PopResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+type TouchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Namespace     string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Expire        *durationpb.Duration   `protobuf:"bytes,3,opt,name=expire,proto3" json:"expire,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchRequest) Reset() {
+	*x = TouchRequest{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchRequest) ProtoMessage() {}
+
+func (x *TouchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchRequest.ProtoReflect.Descriptor instead.
+func (*TouchRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *TouchRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *TouchRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TouchRequest) GetExpire() *durationpb.Duration {
+	if x != nil {
+		return x.Expire
+	}
+	return nil
+}
+
+type TouchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// found is false when the key does not exist.
+	Found         bool `protobuf:"varint,1,opt,name=found,proto3" json:"found,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TouchResponse) Reset() {
+	*x = TouchResponse{}
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TouchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TouchResponse) ProtoMessage() {}
+
+func (x *TouchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_namespaced_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TouchResponse.ProtoReflect.Descriptor instead.
+func (*TouchResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_namespaced_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *TouchResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+var File_cacheserver_v1_namespaced_proto protoreflect.FileDescriptor
+
+const file_cacheserver_v1_namespaced_proto_rawDesc = "" +
+	"\n" +
+	"\x1fcacheserver/v1/namespaced.proto\x12\x0ecacheserver.v1\x1a\x19google/protobuf/any.proto\x1a\x1egoogle/protobuf/duration.proto\"\xab\x01\n" +
+	"\n" +
+	"SetRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x03 \x01(\v2\x14.google.protobuf.AnyR\x05value\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"<\n" +
+	"\n" +
+	"DelRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"\x94\x01\n" +
+	"\n" +
+	"GetRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12D\n" +
+	"\rslidingExpire\x18\x03 \x01(\v2\x19.google.protobuf.DurationH\x00R\rslidingExpire\x88\x01\x01B\x10\n" +
+	"\x0e_slidingExpire\"\x9c\x01\n" +
+	"\vGetResponse\x12*\n" +
+	"\x05value\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x05value\x121\n" +
+	"\x06expire\x18\x02 \x01(\v2\x19.google.protobuf.DurationR\x06expire\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12\x14\n" +
+	"\x05stale\x18\x04 \x01(\bR\x05stale\"\xcf\x01\n" +
+	"\x14CompareAndSetRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x03 \x01(\v2\x14.google.protobuf.AnyR\x05value\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversionB\t\n" +
+	"\a_expire\"O\n" +
+	"\x15CompareAndSetResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x03R\aversion\"c\n" +
+	"\x17CompareAndDeleteRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"8\n" +
+	"\x18CompareAndDeleteResponse\x12\x1c\n" +
+	"\tsucceeded\x18\x01 \x01(\bR\tsucceeded\"\x80\x01\n" +
+	"\vIncrRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x126\n" +
+	"\x06expire\x18\x03 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"\x98\x01\n" +
+	"\rIncrByRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"\x80\x01\n" +
+	"\vDecrRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x126\n" +
+	"\x06expire\x18\x03 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"'\n" +
+	"\x0fCounterResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\"\xaf\x01\n" +
+	"\x12IncrWithCapRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05delta\x18\x03 \x01(\x03R\x05delta\x12\x10\n" +
+	"\x03cap\x18\x04 \x01(\x03R\x03cap\x126\n" +
+	"\x06expire\x18\x05 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"E\n" +
+	"\x13IncrWithCapResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\x03R\x05value\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\"\x92\x02\n" +
+	"\vHSetRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12?\n" +
+	"\x06fields\x18\x03 \x03(\v2'.cacheserver.v1.HSetRequest.FieldsEntryR\x06fields\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01\x1aO\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01B\t\n" +
+	"\a_expire\"$\n" +
+	"\fHSetResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x03R\x05added\"S\n" +
+	"\vHGetRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05field\x18\x03 \x01(\tR\x05field\"P\n" +
+	"\fHGetResponse\x12*\n" +
+	"\x05value\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x05value\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"U\n" +
+	"\vHDelRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06fields\x18\x03 \x03(\tR\x06fields\"(\n" +
+	"\fHDelResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\x03R\adeleted\"@\n" +
+	"\x0eHGetAllRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"\xa7\x01\n" +
+	"\x0fHGetAllResponse\x12C\n" +
+	"\x06fields\x18\x01 \x03(\v2+.cacheserver.v1.HGetAllResponse.FieldsEntryR\x06fields\x1aO\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12*\n" +
+	"\x05value\x18\x02 \x01(\v2\x14.google.protobuf.AnyR\x05value:\x028\x01\"7\n" +
+	"\aZMember\x12\x16\n" +
+	"\x06member\x18\x01 \x01(\tR\x06member\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\"\xb3\x01\n" +
+	"\vZAddRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x121\n" +
+	"\amembers\x18\x03 \x03(\v2\x17.cacheserver.v1.ZMemberR\amembers\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"$\n" +
+	"\fZAddResponse\x12\x14\n" +
+	"\x05added\x18\x01 \x01(\x03R\x05added\"\x98\x01\n" +
+	"\x14ZRangeByScoreRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x10\n" +
+	"\x03min\x18\x03 \x01(\tR\x03min\x12\x10\n" +
+	"\x03max\x18\x04 \x01(\tR\x03max\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x03R\x06offset\x12\x14\n" +
+	"\x05count\x18\x06 \x01(\x03R\x05count\"J\n" +
+	"\x15ZRangeByScoreResponse\x121\n" +
+	"\amembers\x18\x01 \x03(\v2\x17.cacheserver.v1.ZMemberR\amembers\"V\n" +
+	"\fZRankRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06member\x18\x03 \x01(\tR\x06member\"9\n" +
+	"\rZRankResponse\x12\x12\n" +
+	"\x04rank\x18\x01 \x01(\x03R\x04rank\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"\xb1\x01\n" +
+	"\x0eZIncrByRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x16\n" +
+	"\x06member\x18\x03 \x01(\tR\x06member\x12\x14\n" +
+	"\x05delta\x18\x04 \x01(\x01R\x05delta\x126\n" +
+	"\x06expire\x18\x05 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"'\n" +
+	"\x0fZIncrByResponse\x12\x14\n" +
+	"\x05score\x18\x01 \x01(\x01R\x05score\"\xaf\x01\n" +
+	"\fLPushRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12,\n" +
+	"\x06values\x18\x03 \x03(\v2\x14.google.protobuf.AnyR\x06values\x126\n" +
+	"\x06expire\x18\x04 \x01(\v2\x19.google.protobuf.DurationH\x00R\x06expire\x88\x01\x01B\t\n" +
+	"\a_expire\"'\n" +
+	"\rLPushResponse\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\x03R\x06length\"=\n" +
+	"\vRPopRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\"s\n" +
+	"\fBRPopRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x123\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"O\n" +
+	"\vPopResponse\x12*\n" +
+	"\x05value\x18\x01 \x01(\v2\x14.google.protobuf.AnyR\x05value\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\"q\n" +
+	"\fTouchRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x121\n" +
+	"\x06expire\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\x06expire\"%\n" +
+	"\rTouchResponse\x12\x14\n" +
+	"\x05found\x18\x01 \x01(\bR\x05foundB#Z!cacheserver/api/cacheserver/v1;v1b\x06proto3"
 
 var (
 	file_cacheserver_v1_namespaced_proto_rawDescOnce sync.Once
@@ -283,25 +2390,83 @@ func file_cacheserver_v1_namespaced_proto_rawDescGZIP() []byte {
 	return file_cacheserver_v1_namespaced_proto_rawDescData
 }
 
-var file_cacheserver_v1_namespaced_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_cacheserver_v1_namespaced_proto_msgTypes = make([]protoimpl.MessageInfo, 40)
 var file_cacheserver_v1_namespaced_proto_goTypes = []any{
-	(*SetRequest)(nil),          // 0: cacheserver.v1.SetRequest
-	(*DelRequest)(nil),          // 1: cacheserver.v1.DelRequest
-	(*GetRequest)(nil),          // 2: cacheserver.v1.GetRequest
-	(*GetResponse)(nil),         // 3: cacheserver.v1.GetResponse
-	(*anypb.Any)(nil),           // 4: google.protobuf.Any
-	(*durationpb.Duration)(nil), // 5: google.protobuf.Duration
+	(*SetRequest)(nil),               // 0: cacheserver.v1.SetRequest
+	(*DelRequest)(nil),               // 1: cacheserver.v1.DelRequest
+	(*GetRequest)(nil),               // 2: cacheserver.v1.GetRequest
+	(*GetResponse)(nil),              // 3: cacheserver.v1.GetResponse
+	(*CompareAndSetRequest)(nil),     // 4: cacheserver.v1.CompareAndSetRequest
+	(*CompareAndSetResponse)(nil),    // 5: cacheserver.v1.CompareAndSetResponse
+	(*CompareAndDeleteRequest)(nil),  // 6: cacheserver.v1.CompareAndDeleteRequest
+	(*CompareAndDeleteResponse)(nil), // 7: cacheserver.v1.CompareAndDeleteResponse
+	(*IncrRequest)(nil),              // 8: cacheserver.v1.IncrRequest
+	(*IncrByRequest)(nil),            // 9: cacheserver.v1.IncrByRequest
+	(*DecrRequest)(nil),              // 10: cacheserver.v1.DecrRequest
+	(*CounterResponse)(nil),          // 11: cacheserver.v1.CounterResponse
+	(*IncrWithCapRequest)(nil),       // 12: cacheserver.v1.IncrWithCapRequest
+	(*IncrWithCapResponse)(nil),      // 13: cacheserver.v1.IncrWithCapResponse
+	(*HSetRequest)(nil),              // 14: cacheserver.v1.HSetRequest
+	(*HSetResponse)(nil),             // 15: cacheserver.v1.HSetResponse
+	(*HGetRequest)(nil),              // 16: cacheserver.v1.HGetRequest
+	(*HGetResponse)(nil),             // 17: cacheserver.v1.HGetResponse
+	(*HDelRequest)(nil),              // 18: cacheserver.v1.HDelRequest
+	(*HDelResponse)(nil),             // 19: cacheserver.v1.HDelResponse
+	(*HGetAllRequest)(nil),           // 20: cacheserver.v1.HGetAllRequest
+	(*HGetAllResponse)(nil),          // 21: cacheserver.v1.HGetAllResponse
+	(*ZMember)(nil),                  // 22: cacheserver.v1.ZMember
+	(*ZAddRequest)(nil),              // 23: cacheserver.v1.ZAddRequest
+	(*ZAddResponse)(nil),             // 24: cacheserver.v1.ZAddResponse
+	(*ZRangeByScoreRequest)(nil),     // 25: cacheserver.v1.ZRangeByScoreRequest
+	(*ZRangeByScoreResponse)(nil),    // 26: cacheserver.v1.ZRangeByScoreResponse
+	(*ZRankRequest)(nil),             // 27: cacheserver.v1.ZRankRequest
+	(*ZRankResponse)(nil),            // 28: cacheserver.v1.ZRankResponse
+	(*ZIncrByRequest)(nil),           // 29: cacheserver.v1.ZIncrByRequest
+	(*ZIncrByResponse)(nil),          // 30: cacheserver.v1.ZIncrByResponse
+	(*LPushRequest)(nil),             // 31: cacheserver.v1.LPushRequest
+	(*LPushResponse)(nil),            // 32: cacheserver.v1.LPushResponse
+	(*RPopRequest)(nil),              // 33: cacheserver.v1.RPopRequest
+	(*BRPopRequest)(nil),             // 34: cacheserver.v1.BRPopRequest
+	(*PopResponse)(nil),              // 35: cacheserver.v1.PopResponse
+	(*TouchRequest)(nil),             // 36: cacheserver.v1.TouchRequest
+	(*TouchResponse)(nil),            // 37: cacheserver.v1.TouchResponse
+	nil,                              // 38: cacheserver.v1.HSetRequest.FieldsEntry
+	nil,                              // 39: cacheserver.v1.HGetAllResponse.FieldsEntry
+	(*anypb.Any)(nil),                // 40: google.protobuf.Any
+	(*durationpb.Duration)(nil),      // 41: google.protobuf.Duration
 }
 var file_cacheserver_v1_namespaced_proto_depIdxs = []int32{
-	4, // 0: cacheserver.v1.SetRequest.value:type_name -> google.protobuf.Any
-	5, // 1: cacheserver.v1.SetRequest.expire:type_name -> google.protobuf.Duration
-	4, // 2: cacheserver.v1.GetResponse.value:type_name -> google.protobuf.Any
-	5, // 3: cacheserver.v1.GetResponse.expire:type_name -> google.protobuf.Duration
-	4, // [4:4] is the sub-list for method output_type
-	4, // [4:4] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	40, // 0: cacheserver.v1.SetRequest.value:type_name -> google.protobuf.Any
+	41, // 1: cacheserver.v1.SetRequest.expire:type_name -> google.protobuf.Duration
+	41, // 2: cacheserver.v1.GetRequest.slidingExpire:type_name -> google.protobuf.Duration
+	40, // 3: cacheserver.v1.GetResponse.value:type_name -> google.protobuf.Any
+	41, // 4: cacheserver.v1.GetResponse.expire:type_name -> google.protobuf.Duration
+	40, // 5: cacheserver.v1.CompareAndSetRequest.value:type_name -> google.protobuf.Any
+	41, // 6: cacheserver.v1.CompareAndSetRequest.expire:type_name -> google.protobuf.Duration
+	41, // 7: cacheserver.v1.IncrRequest.expire:type_name -> google.protobuf.Duration
+	41, // 8: cacheserver.v1.IncrByRequest.expire:type_name -> google.protobuf.Duration
+	41, // 9: cacheserver.v1.DecrRequest.expire:type_name -> google.protobuf.Duration
+	41, // 10: cacheserver.v1.IncrWithCapRequest.expire:type_name -> google.protobuf.Duration
+	38, // 11: cacheserver.v1.HSetRequest.fields:type_name -> cacheserver.v1.HSetRequest.FieldsEntry
+	41, // 12: cacheserver.v1.HSetRequest.expire:type_name -> google.protobuf.Duration
+	40, // 13: cacheserver.v1.HGetResponse.value:type_name -> google.protobuf.Any
+	39, // 14: cacheserver.v1.HGetAllResponse.fields:type_name -> cacheserver.v1.HGetAllResponse.FieldsEntry
+	22, // 15: cacheserver.v1.ZAddRequest.members:type_name -> cacheserver.v1.ZMember
+	41, // 16: cacheserver.v1.ZAddRequest.expire:type_name -> google.protobuf.Duration
+	22, // 17: cacheserver.v1.ZRangeByScoreResponse.members:type_name -> cacheserver.v1.ZMember
+	41, // 18: cacheserver.v1.ZIncrByRequest.expire:type_name -> google.protobuf.Duration
+	40, // 19: cacheserver.v1.LPushRequest.values:type_name -> google.protobuf.Any
+	41, // 20: cacheserver.v1.LPushRequest.expire:type_name -> google.protobuf.Duration
+	41, // 21: cacheserver.v1.BRPopRequest.timeout:type_name -> google.protobuf.Duration
+	40, // 22: cacheserver.v1.PopResponse.value:type_name -> google.protobuf.Any
+	41, // 23: cacheserver.v1.TouchRequest.expire:type_name -> google.protobuf.Duration
+	40, // 24: cacheserver.v1.HSetRequest.FieldsEntry.value:type_name -> google.protobuf.Any
+	40, // 25: cacheserver.v1.HGetAllResponse.FieldsEntry.value:type_name -> google.protobuf.Any
+	26, // [26:26] is the sub-list for method output_type
+	26, // [26:26] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
 }
 
 func init() { file_cacheserver_v1_namespaced_proto_init() }
@@ -310,13 +2475,23 @@ func file_cacheserver_v1_namespaced_proto_init() {
 		return
 	}
 	file_cacheserver_v1_namespaced_proto_msgTypes[0].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[2].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[4].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[8].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[9].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[10].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[12].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[14].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[23].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[29].OneofWrappers = []any{}
+	file_cacheserver_v1_namespaced_proto_msgTypes[31].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cacheserver_v1_namespaced_proto_rawDesc), len(file_cacheserver_v1_namespaced_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   4,
+			NumMessages:   40,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
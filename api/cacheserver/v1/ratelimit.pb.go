@@ -0,0 +1,257 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v5.29.3
+// source: cacheserver/v1/ratelimit.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RateLimitRequest asks whether cost units may be consumed from a limit of
+// limit units per window. The limit is enforced with GCRA in Redis.
+type RateLimitRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Namespace string                 `protobuf:"bytes,1,opt,name=namespace,proto3" json:"namespace,omitempty"`
+	Key       string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Limit     int64                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Window    *durationpb.Duration   `protobuf:"bytes,4,opt,name=window,proto3" json:"window,omitempty"`
+	// cost defaults to 1 and must not exceed limit.
+	Cost int64 `protobuf:"varint,5,opt,name=cost,proto3" json:"cost,omitempty"`
+	// localPrecheck enables a per-instance token bucket that rejects callers
+	// already over the limit without a Redis round trip.
+	LocalPrecheck bool `protobuf:"varint,6,opt,name=localPrecheck,proto3" json:"localPrecheck,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitRequest) Reset() {
+	*x = RateLimitRequest{}
+	mi := &file_cacheserver_v1_ratelimit_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitRequest) ProtoMessage() {}
+
+func (x *RateLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_ratelimit_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitRequest.ProtoReflect.Descriptor instead.
+func (*RateLimitRequest) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_ratelimit_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RateLimitRequest) GetNamespace() string {
+	if x != nil {
+		return x.Namespace
+	}
+	return ""
+}
+
+func (x *RateLimitRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *RateLimitRequest) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *RateLimitRequest) GetWindow() *durationpb.Duration {
+	if x != nil {
+		return x.Window
+	}
+	return nil
+}
+
+func (x *RateLimitRequest) GetCost() int64 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *RateLimitRequest) GetLocalPrecheck() bool {
+	if x != nil {
+		return x.LocalPrecheck
+	}
+	return false
+}
+
+type RateLimitResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Allowed   bool                   `protobuf:"varint,1,opt,name=allowed,proto3" json:"allowed,omitempty"`
+	Remaining int64                  `protobuf:"varint,2,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	// retryAfter is how long to wait before the request could be allowed; zero when allowed.
+	RetryAfter *durationpb.Duration `protobuf:"bytes,3,opt,name=retryAfter,proto3" json:"retryAfter,omitempty"`
+	// resetAfter is how long until the limit is fully replenished.
+	ResetAfter    *durationpb.Duration `protobuf:"bytes,4,opt,name=resetAfter,proto3" json:"resetAfter,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimitResponse) Reset() {
+	*x = RateLimitResponse{}
+	mi := &file_cacheserver_v1_ratelimit_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimitResponse) ProtoMessage() {}
+
+func (x *RateLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cacheserver_v1_ratelimit_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimitResponse.ProtoReflect.Descriptor instead.
+func (*RateLimitResponse) Descriptor() ([]byte, []int) {
+	return file_cacheserver_v1_ratelimit_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *RateLimitResponse) GetAllowed() bool {
+	if x != nil {
+		return x.Allowed
+	}
+	return false
+}
+
+func (x *RateLimitResponse) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *RateLimitResponse) GetRetryAfter() *durationpb.Duration {
+	if x != nil {
+		return x.RetryAfter
+	}
+	return nil
+}
+
+func (x *RateLimitResponse) GetResetAfter() *durationpb.Duration {
+	if x != nil {
+		return x.ResetAfter
+	}
+	return nil
+}
+
+var File_cacheserver_v1_ratelimit_proto protoreflect.FileDescriptor
+
+const file_cacheserver_v1_ratelimit_proto_rawDesc = "" +
+	"\n" +
+	"\x1ecacheserver/v1/ratelimit.proto\x12\x0ecacheserver.v1\x1a\x1egoogle/protobuf/duration.proto\"\xc5\x01\n" +
+	"\x10RateLimitRequest\x12\x1c\n" +
+	"\tnamespace\x18\x01 \x01(\tR\tnamespace\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x03R\x05limit\x121\n" +
+	"\x06window\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\x06window\x12\x12\n" +
+	"\x04cost\x18\x05 \x01(\x03R\x04cost\x12$\n" +
+	"\rlocalPrecheck\x18\x06 \x01(\bR\rlocalPrecheck\"\xc1\x01\n" +
+	"\x11RateLimitResponse\x12\x18\n" +
+	"\aallowed\x18\x01 \x01(\bR\aallowed\x12\x1c\n" +
+	"\tremaining\x18\x02 \x01(\x03R\tremaining\x129\n" +
+	"\n" +
+	"retryAfter\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"retryAfter\x129\n" +
+	"\n" +
+	"resetAfter\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\n" +
+	"resetAfterB#Z!cacheserver/api/cacheserver/v1;v1b\x06proto3"
+
+var (
+	file_cacheserver_v1_ratelimit_proto_rawDescOnce sync.Once
+	file_cacheserver_v1_ratelimit_proto_rawDescData []byte
+)
+
+func file_cacheserver_v1_ratelimit_proto_rawDescGZIP() []byte {
+	file_cacheserver_v1_ratelimit_proto_rawDescOnce.Do(func() {
+		file_cacheserver_v1_ratelimit_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cacheserver_v1_ratelimit_proto_rawDesc), len(file_cacheserver_v1_ratelimit_proto_rawDesc)))
+	})
+	return file_cacheserver_v1_ratelimit_proto_rawDescData
+}
+
+var file_cacheserver_v1_ratelimit_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_cacheserver_v1_ratelimit_proto_goTypes = []any{
+	(*RateLimitRequest)(nil),    // 0: cacheserver.v1.RateLimitRequest
+	(*RateLimitResponse)(nil),   // 1: cacheserver.v1.RateLimitResponse
+	(*durationpb.Duration)(nil), // 2: google.protobuf.Duration
+}
+var file_cacheserver_v1_ratelimit_proto_depIdxs = []int32{
+	2, // 0: cacheserver.v1.RateLimitRequest.window:type_name -> google.protobuf.Duration
+	2, // 1: cacheserver.v1.RateLimitResponse.retryAfter:type_name -> google.protobuf.Duration
+	2, // 2: cacheserver.v1.RateLimitResponse.resetAfter:type_name -> google.protobuf.Duration
+	3, // [3:3] is the sub-list for method output_type
+	3, // [3:3] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_cacheserver_v1_ratelimit_proto_init() }
+func file_cacheserver_v1_ratelimit_proto_init() {
+	if File_cacheserver_v1_ratelimit_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cacheserver_v1_ratelimit_proto_rawDesc), len(file_cacheserver_v1_ratelimit_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_cacheserver_v1_ratelimit_proto_goTypes,
+		DependencyIndexes: file_cacheserver_v1_ratelimit_proto_depIdxs,
+		MessageInfos:      file_cacheserver_v1_ratelimit_proto_msgTypes,
+	}.Build()
+	File_cacheserver_v1_ratelimit_proto = out.File
+	file_cacheserver_v1_ratelimit_proto_goTypes = nil
+	file_cacheserver_v1_ratelimit_proto_depIdxs = nil
+}
@@ -31,14 +31,44 @@ func wireApp(confServer *conf.Server, confData *conf.Data, logger log.Logger) (*
 	greeterRepo := data.NewGreeterRepo(dataData, logger)
 	greeterUsecase := biz.NewGreeterUsecase(greeterRepo, logger)
 	greeterService := service.NewGreeterService(greeterUsecase)
-	namespacedCache := data.NewNamespacedCache(dataData, logger)
-	secretChainStore := data.NewSecretChainCache(dataData, logger)
-	cacheBiz := biz.NewCacheBiz(namespacedCache, secretChainStore)
+	namespacedCache, cleanup2, err := data.NewNamespacedCache(confData, dataData, logger)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	eventLog, cleanup3 := data.NewEventLog(confData, dataData, logger)
+	secretChainStore, cleanup4, err := data.NewSecretChainCache(confData, dataData, logger)
+	if err != nil {
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	auditStore, cleanup5 := data.NewAuditStore(dataData, logger)
+	rateLimiter, cleanup6 := data.NewRateLimiter(dataData)
+	redisLocker := data.NewRedisLocker(dataData)
+	cacheBiz := biz.NewCacheBiz(namespacedCache, eventLog, secretChainStore, auditStore, rateLimiter, redisLocker)
 	cacheServerService := service.NewCacheServerService(cacheBiz)
 	grpcServer := server.NewGRPCServer(confServer, greeterService, cacheServerService, logger)
-	httpServer := server.NewHTTPServer(confServer, greeterService, logger)
+	metrics, cleanup7, err := server.NewMetrics(logger)
+	if err != nil {
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
+		cleanup()
+		return nil, nil, err
+	}
+	httpServer := server.NewHTTPServer(confServer, greeterService, metrics, logger)
 	app := newApp(logger, grpcServer, httpServer)
 	return app, func() {
+		cleanup7()
+		cleanup6()
+		cleanup5()
+		cleanup4()
+		cleanup3()
+		cleanup2()
 		cleanup()
 	}, nil
 }
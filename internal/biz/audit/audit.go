@@ -0,0 +1,160 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	v1 "cacheserver/api/cacheserver/v1"
+)
+
+// Actions recorded in the audit log.
+const (
+	ActionSetSecret      = "SetSecret"
+	ActionGetSecret      = "GetSecret"
+	ActionDelSecret      = "DelSecret"
+	ActionUndeleteSecret = "UndeleteSecret"
+	ActionPurgeSecret    = "PurgeSecret"
+)
+
+// Outcomes recorded in the audit log.
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// ClaimedUserIDHeader is the request header carrying the identity claimed by
+// the caller. It is not authenticated, so it is only recorded as a claim.
+const ClaimedUserIDHeader = "x-user-id"
+
+// AuditBiz defines the interface for handling audit log requests.
+type AuditBiz interface {
+	List(ctx context.Context, rq *v1.ListAuditEventsRequest) (*v1.ListAuditEventsResponse, error)
+}
+
+// EventM represents an audit event model.
+type EventM struct {
+	ID       int64
+	Action   string
+	SecretID string
+	// ClaimedUserID is the unauthenticated caller identity taken from the
+	// ClaimedUserIDHeader; any client can set it to any value.
+	ClaimedUserID string
+	Peer          string
+	Outcome       string
+	Error         string
+	TraceID       string
+	CreatedAt     time.Time
+}
+
+// ListOptions holds the filters used when listing audit events.
+type ListOptions struct {
+	SecretID      string
+	ClaimedUserID string
+	Start         time.Time
+	End           time.Time
+	Offset        int
+	Limit         int
+}
+
+// AuditStore defines the interface for audit storage operations.
+type AuditStore interface {
+	// Record enqueues an event for asynchronous persistence. It must not block.
+	Record(ctx context.Context, event *EventM)
+	List(ctx context.Context, opts *ListOptions) (int64, []*EventM, error)
+}
+
+const (
+	// defaultListLimit is used when a list request does not specify a limit.
+	defaultListLimit = 100
+	// maxListLimit is the largest number of events returned by a list request.
+	maxListLimit = 1000
+)
+
+// auditBiz is the implementation of AuditBiz.
+type auditBiz struct {
+	store AuditStore
+}
+
+// Ensure that *auditBiz implements the AuditBiz.
+var _ AuditBiz = (*auditBiz)(nil)
+
+// New creates and returns a new instance of *auditBiz.
+func New(store AuditStore) AuditBiz {
+	return &auditBiz{store: store}
+}
+
+// List returns the audit events matching the given filters, newest first.
+func (b *auditBiz) List(ctx context.Context, rq *v1.ListAuditEventsRequest) (*v1.ListAuditEventsResponse, error) {
+	opts := &ListOptions{
+		SecretID:      rq.SecretID,
+		ClaimedUserID: rq.ClaimedUserID,
+		Offset:        int(rq.Offset),
+		Limit:         int(rq.Limit),
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultListLimit
+	}
+	if opts.Limit > maxListLimit {
+		opts.Limit = maxListLimit
+	}
+	if opts.Offset < 0 {
+		opts.Offset = 0
+	}
+	if rq.StartTime != nil {
+		opts.Start = rq.StartTime.AsTime()
+	}
+	if rq.EndTime != nil {
+		opts.End = rq.EndTime.AsTime()
+	}
+
+	total, events, err := b.store.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListAuditEventsResponse{TotalCount: total, Events: make([]*v1.AuditEvent, 0, len(events))}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &v1.AuditEvent{
+			Id:            event.ID,
+			Action:        event.Action,
+			SecretID:      event.SecretID,
+			ClaimedUserID: event.ClaimedUserID,
+			Peer:          event.Peer,
+			Outcome:       event.Outcome,
+			Error:         event.Error,
+			TraceID:       event.TraceID,
+			CreatedAt:     timestamppb.New(event.CreatedAt),
+		})
+	}
+	return resp, nil
+}
+
+// NewEvent builds an audit event for the given action, filling in the claimed
+// caller identity, peer address and trace ID from the request context.
+func NewEvent(ctx context.Context, action string, secretID string, err error) *EventM {
+	event := &EventM{
+		Action:    action,
+		SecretID:  secretID,
+		Outcome:   OutcomeSuccess,
+		CreatedAt: time.Now(),
+	}
+	if err != nil {
+		event.Outcome = OutcomeFailure
+		event.Error = err.Error()
+	}
+	if tr, ok := transport.FromServerContext(ctx); ok {
+		event.ClaimedUserID = tr.RequestHeader().Get(ClaimedUserIDHeader)
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		event.Peer = p.Addr.String()
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.HasTraceID() {
+		event.TraceID = sc.TraceID().String()
+	}
+	return event
+}
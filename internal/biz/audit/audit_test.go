@@ -0,0 +1,130 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/go-kratos/kratos/v2/transport"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/peer"
+
+	v1 "cacheserver/api/cacheserver/v1"
+)
+
+// headerCarrier is a transport.Header over http.Header.
+type headerCarrier http.Header
+
+func (h headerCarrier) Get(key string) string      { return http.Header(h).Get(key) }
+func (h headerCarrier) Set(key, value string)      { http.Header(h).Set(key, value) }
+func (h headerCarrier) Add(key, value string)      { http.Header(h).Add(key, value) }
+func (h headerCarrier) Values(key string) []string { return http.Header(h).Values(key) }
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// serverTransport is a server transport.Transporter with request headers.
+type serverTransport struct {
+	header headerCarrier
+}
+
+func (t *serverTransport) Kind() transport.Kind            { return transport.KindGRPC }
+func (t *serverTransport) Endpoint() string                { return "" }
+func (t *serverTransport) Operation() string               { return "/cacheserver.v1.CacheServer/GetSecret" }
+func (t *serverTransport) RequestHeader() transport.Header { return t.header }
+func (t *serverTransport) ReplyHeader() transport.Header   { return headerCarrier{} }
+
+func TestNewEvent(t *testing.T) {
+	traceID := trace.TraceID{1, 2, 3}
+	tr := &serverTransport{header: headerCarrier{}}
+	tr.header.Set(ClaimedUserIDHeader, "alice")
+
+	ctx := transport.NewServerContext(context.Background(), tr)
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 4000}})
+	ctx = trace.ContextWithSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1},
+	}))
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		err     error
+		want    EventM
+		wantErr string
+	}{
+		{
+			name: "success",
+			ctx:  ctx,
+			want: EventM{ClaimedUserID: "alice", Peer: "10.0.0.1:4000", Outcome: OutcomeSuccess, TraceID: traceID.String()},
+		},
+		{
+			name: "failure",
+			ctx:  ctx,
+			err:  errors.New("boom"),
+			want: EventM{ClaimedUserID: "alice", Peer: "10.0.0.1:4000", Outcome: OutcomeFailure, Error: "boom", TraceID: traceID.String()},
+		},
+		{
+			name: "no request metadata",
+			ctx:  context.Background(),
+			want: EventM{Outcome: OutcomeSuccess},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := NewEvent(tt.ctx, ActionGetSecret, "s1", tt.err)
+			if event.CreatedAt.IsZero() {
+				t.Fatal("event has no creation time")
+			}
+			tt.want.Action, tt.want.SecretID, tt.want.CreatedAt = ActionGetSecret, "s1", event.CreatedAt
+			if *event != tt.want {
+				t.Fatalf("NewEvent = %+v, want %+v", *event, tt.want)
+			}
+		})
+	}
+}
+
+// listStore is an AuditStore recording the options of the last List.
+type listStore struct {
+	opts *ListOptions
+}
+
+func (s *listStore) Record(context.Context, *EventM) {}
+
+func (s *listStore) List(_ context.Context, opts *ListOptions) (int64, []*EventM, error) {
+	s.opts = opts
+	return 0, nil, nil
+}
+
+func TestListBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		rq         *v1.ListAuditEventsRequest
+		wantLimit  int
+		wantOffset int
+	}{
+		{name: "default limit", rq: &v1.ListAuditEventsRequest{}, wantLimit: defaultListLimit},
+		{name: "limit within bounds", rq: &v1.ListAuditEventsRequest{Limit: 10, Offset: 20}, wantLimit: 10, wantOffset: 20},
+		{name: "limit capped", rq: &v1.ListAuditEventsRequest{Limit: 5000}, wantLimit: maxListLimit},
+		{name: "negative offset", rq: &v1.ListAuditEventsRequest{Limit: -1, Offset: -5}, wantLimit: defaultListLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &listStore{}
+			if _, err := New(store).List(context.Background(), tt.rq); err != nil {
+				t.Fatal(err)
+			}
+			if store.opts.Limit != tt.wantLimit || store.opts.Offset != tt.wantOffset {
+				t.Fatalf("List(limit=%d, offset=%d) queried limit=%d, offset=%d, want %d, %d",
+					tt.rq.Limit, tt.rq.Offset, store.opts.Limit, store.opts.Offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -3,7 +3,10 @@ package biz
 import (
 	"github.com/google/wire"
 
+	"cacheserver/internal/biz/audit"
+	"cacheserver/internal/biz/lock"
 	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/biz/ratelimit"
 	"cacheserver/internal/biz/secret"
 )
 
@@ -14,28 +17,57 @@ var ProviderSet = wire.NewSet(NewGreeterUsecase, NewCacheBiz, wire.Bind(new(ICac
 type ICacheBiz interface {
 	NamespacedV1(namespace string) namespaced.NamespacedBiz
 	SecretV1() secret.SecretBiz
+	AuditV1() audit.AuditBiz
+	RateLimitV1() ratelimit.RateLimitBiz
+	LockV1() lock.LockBiz
 }
 
 // CacheBiz is a concrete implementation of ICacheBiz.
 type CacheBiz struct {
 	cache       namespaced.Cache
+	events      namespaced.EventLog
 	secretStore secret.SecretStore
+	auditStore  audit.AuditStore
+	limiter     ratelimit.Limiter
+	locker      lock.Locker
 }
 
 // Ensure that CacheBiz implements the ICacheBiz.
 var _ ICacheBiz = (*CacheBiz)(nil)
 
 // NewCacheBiz creates an instance of ICacheBiz.
-func NewCacheBiz(cache namespaced.Cache, secretStore secret.SecretStore) *CacheBiz {
-	return &CacheBiz{cache: cache, secretStore: secretStore}
+func NewCacheBiz(
+	cache namespaced.Cache,
+	events namespaced.EventLog,
+	secretStore secret.SecretStore,
+	auditStore audit.AuditStore,
+	limiter ratelimit.Limiter,
+	locker lock.Locker,
+) *CacheBiz {
+	return &CacheBiz{cache: cache, events: events, secretStore: secretStore, auditStore: auditStore, limiter: limiter, locker: locker}
 }
 
 // NamespacedV1 returns an instance that implements the NamespacedBiz.
 func (b *CacheBiz) NamespacedV1(namespace string) namespaced.NamespacedBiz {
-	return namespaced.New(b.cache, namespace)
+	return namespaced.New(b.cache, b.events, namespace)
 }
 
 // SecretV1 returns an instance that implements the SecretBiz.
 func (b *CacheBiz) SecretV1() secret.SecretBiz {
-	return secret.New(b.secretStore)
+	return secret.New(b.secretStore, b.auditStore)
+}
+
+// AuditV1 returns an instance that implements the AuditBiz.
+func (b *CacheBiz) AuditV1() audit.AuditBiz {
+	return audit.New(b.auditStore)
+}
+
+// RateLimitV1 returns an instance that implements the RateLimitBiz.
+func (b *CacheBiz) RateLimitV1() ratelimit.RateLimitBiz {
+	return ratelimit.New(b.limiter)
+}
+
+// LockV1 returns an instance that implements the LockBiz.
+func (b *CacheBiz) LockV1() lock.LockBiz {
+	return lock.New(b.locker)
 }
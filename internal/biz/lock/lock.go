@@ -0,0 +1,127 @@
+package lock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/google/uuid"
+
+	v1 "cacheserver/api/cacheserver/v1"
+)
+
+const (
+	// minRetryInterval is the initial delay between attempts of a blocking acquire.
+	minRetryInterval = 10 * time.Millisecond
+	// maxRetryInterval caps the delay between attempts of a blocking acquire.
+	maxRetryInterval = 200 * time.Millisecond
+)
+
+// LockBiz defines the interface for handling distributed lock requests.
+type LockBiz interface {
+	Acquire(ctx context.Context, rq *v1.AcquireLockRequest) (*v1.AcquireLockResponse, error)
+	Renew(ctx context.Context, rq *v1.RenewLockRequest) (*v1.RenewLockResponse, error)
+	Release(ctx context.Context, rq *v1.ReleaseLockRequest) (*v1.ReleaseLockResponse, error)
+}
+
+// Locker defines the interface for lock storage operations.
+type Locker interface {
+	// Acquire takes the lock for token if it is free or already held by
+	// token. It returns the fencing token and whether the lock was acquired.
+	Acquire(ctx context.Context, key string, token string, ttl time.Duration) (int64, bool, error)
+	// Renew extends the lease if the lock is held by token.
+	Renew(ctx context.Context, key string, token string, ttl time.Duration) (bool, error)
+	// Release frees the lock if it is held by token.
+	Release(ctx context.Context, key string, token string) (bool, error)
+}
+
+// LockKey represents a lock name within a namespace.
+type LockKey struct {
+	Namespace string
+	Key       string
+}
+
+// CacheKey returns the cache key for the LockKey. The namespace is a Redis
+// Cluster hash tag, so that the lock and its fencing counter share a slot.
+func (k LockKey) CacheKey() string {
+	return fmt.Sprintf("lock:{%s}:%s", k.Namespace, k.Key)
+}
+
+// lockBiz is the implementation of LockBiz.
+type lockBiz struct {
+	locker Locker
+}
+
+// Ensure that *lockBiz implements the LockBiz.
+var _ LockBiz = (*lockBiz)(nil)
+
+// New creates and returns a new instance of *lockBiz.
+func New(locker Locker) LockBiz {
+	return &lockBiz{locker: locker}
+}
+
+// Acquire takes a lock, optionally waiting until it is released or the wait
+// (bounded by the request deadline) elapses.
+func (b *lockBiz) Acquire(ctx context.Context, rq *v1.AcquireLockRequest) (*v1.AcquireLockResponse, error) {
+	if rq.Ttl == nil || rq.Ttl.AsDuration() < time.Millisecond {
+		return nil, errors.BadRequest("INVALID_TTL", "ttl must be at least 1ms")
+	}
+	token := rq.Token
+	if token == "" {
+		token = uuid.New().String()
+	}
+
+	cacheKey := LockKey{rq.Namespace, rq.Key}.CacheKey()
+	deadline := time.Now().Add(rq.Wait.AsDuration())
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	interval := minRetryInterval
+	for {
+		fence, ok, err := b.locker.Acquire(ctx, cacheKey, token, rq.Ttl.AsDuration())
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			return &v1.AcquireLockResponse{Acquired: true, Token: token, FencingToken: fence}, nil
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return &v1.AcquireLockResponse{Acquired: false, Token: token}, nil
+		}
+		timer := time.NewTimer(min(interval, wait))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		interval = min(interval*2, maxRetryInterval)
+	}
+}
+
+// Renew extends the lease of a lock held by the caller.
+func (b *lockBiz) Renew(ctx context.Context, rq *v1.RenewLockRequest) (*v1.RenewLockResponse, error) {
+	if rq.Ttl == nil || rq.Ttl.AsDuration() < time.Millisecond {
+		return nil, errors.BadRequest("INVALID_TTL", "ttl must be at least 1ms")
+	}
+
+	cacheKey := LockKey{rq.Namespace, rq.Key}.CacheKey()
+	ok, err := b.locker.Renew(ctx, cacheKey, rq.Token, rq.Ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.RenewLockResponse{Renewed: ok}, nil
+}
+
+// Release frees a lock held by the caller.
+func (b *lockBiz) Release(ctx context.Context, rq *v1.ReleaseLockRequest) (*v1.ReleaseLockResponse, error) {
+	cacheKey := LockKey{rq.Namespace, rq.Key}.CacheKey()
+	ok, err := b.locker.Release(ctx, cacheKey, rq.Token)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ReleaseLockResponse{Released: ok}, nil
+}
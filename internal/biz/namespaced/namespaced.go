@@ -3,11 +3,14 @@ package namespaced
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/go-kratos/kratos/v2/errors"
 	"google.golang.org/protobuf/types/known/anypb"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	v1 "cacheserver/api/cacheserver/v1"
 )
@@ -16,32 +19,153 @@ import (
 type NamespacedBiz interface {
 	Set(ctx context.Context, key string, value *anypb.Any, ttl *durationpb.Duration) (*emptypb.Empty, error)
 	Del(ctx context.Context, key string) (*emptypb.Empty, error)
-	Get(ctx context.Context, key string) (*v1.GetResponse, error)
+	Get(ctx context.Context, key string, slidingExpire *durationpb.Duration) (*v1.GetResponse, error)
+	Touch(ctx context.Context, key string, ttl *durationpb.Duration) (*v1.TouchResponse, error)
+	CompareAndSet(ctx context.Context, key string, value *anypb.Any, ttl *durationpb.Duration, version int64) (*v1.CompareAndSetResponse, error)
+	CompareAndDelete(ctx context.Context, key string, version int64) (*v1.CompareAndDeleteResponse, error)
+	IncrBy(ctx context.Context, key string, delta int64, ttl *durationpb.Duration) (*v1.CounterResponse, error)
+	IncrWithCap(ctx context.Context, key string, delta int64, cap int64, ttl *durationpb.Duration) (*v1.IncrWithCapResponse, error)
+	Watch(ctx context.Context, rq *v1.WatchRequest, send func(*v1.WatchEvent) error) error
+	HSet(ctx context.Context, key string, fields map[string]*anypb.Any, ttl *durationpb.Duration) (*v1.HSetResponse, error)
+	HGet(ctx context.Context, key string, field string) (*v1.HGetResponse, error)
+	HDel(ctx context.Context, key string, fields []string) (*v1.HDelResponse, error)
+	HGetAll(ctx context.Context, key string) (*v1.HGetAllResponse, error)
+	ZAdd(ctx context.Context, key string, members []*v1.ZMember, ttl *durationpb.Duration) (*v1.ZAddResponse, error)
+	ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) (*v1.ZRangeByScoreResponse, error)
+	ZRank(ctx context.Context, key string, member string) (*v1.ZRankResponse, error)
+	ZIncrBy(ctx context.Context, key string, member string, delta float64, ttl *durationpb.Duration) (*v1.ZIncrByResponse, error)
+	LPush(ctx context.Context, key string, values []*anypb.Any, ttl *durationpb.Duration) (*v1.LPushResponse, error)
+	RPop(ctx context.Context, key string) (*v1.PopResponse, error)
+	BRPop(ctx context.Context, key string, timeout *durationpb.Duration) (*v1.PopResponse, error)
+}
+
+// Entry is a cached value together with its version.
+type Entry struct {
+	Value   *anypb.Any
+	Version int64
+	// Stale is set when the entry has expired but is served because the
+	// shared level failed.
+	Stale bool
+}
+
+// ScoredMember is a member of a sorted set together with its score.
+type ScoredMember struct {
+	Member string
+	Score  float64
 }
 
 // Cache defines the interface for cache operations.
 type Cache interface {
 	Set(ctx context.Context, key string, value *anypb.Any) error
 	SetWithTTL(ctx context.Context, key string, value *anypb.Any, ttl time.Duration) error
-	Get(ctx context.Context, key string) (*anypb.Any, error)
-	GetWithTTL(ctx context.Context, key string) (*anypb.Any, time.Duration, error)
+	Get(ctx context.Context, key string) (*Entry, error)
+	GetWithTTL(ctx context.Context, key string) (*Entry, time.Duration, error)
 	Del(ctx context.Context, key string) error
+	// GetAndTouch reads the value from the shared level and resets its TTL to
+	// ttl, refreshing the local level as well.
+	GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*Entry, error)
+	// Touch resets the TTL of key to ttl and reports whether the key exists.
+	Touch(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// CompareAndSet atomically replaces the value if its current version equals
+	// version (0 meaning absent). It returns the new version and whether the swap happened.
+	CompareAndSet(ctx context.Context, key string, value *anypb.Any, version int64, ttl time.Duration) (int64, bool, error)
+	// CompareAndDelete atomically deletes the value if its current version equals version.
+	CompareAndDelete(ctx context.Context, key string, version int64) (bool, error)
+	// IncrBy atomically adds delta to an integer counter, applying ttl when the
+	// counter has no expiration yet. It returns the new value.
+	IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+	// IncrWithCap is like IncrBy but only applies the increment if the result
+	// does not exceed cap. It returns the resulting value and whether it was applied.
+	IncrWithCap(ctx context.Context, key string, delta int64, cap int64, ttl time.Duration) (int64, bool, error)
+
+	// HSet sets fields of the hash at key, applying ttl to the key when
+	// positive. It returns the number of new fields.
+	HSet(ctx context.Context, key string, fields map[string]*anypb.Any, ttl time.Duration) (int64, error)
+	// HGet returns a field of the hash at key and whether it exists.
+	HGet(ctx context.Context, key string, field string) (*anypb.Any, bool, error)
+	// HDel deletes fields of the hash at key and returns how many existed.
+	HDel(ctx context.Context, key string, fields []string) (int64, error)
+	// HGetAll returns every field of the hash at key.
+	HGetAll(ctx context.Context, key string) (map[string]*anypb.Any, error)
+
+	// Sorted set and list operations run against the shared level only; the
+	// local level is invalidated on writes but never serves them.
+
+	// ZAdd adds or updates members of the sorted set at key, applying ttl to
+	// the key when positive. It returns the number of new members.
+	ZAdd(ctx context.Context, key string, members []ScoredMember, ttl time.Duration) (int64, error)
+	// ZRangeByScore returns members with a score between min and max in
+	// ascending order. A count of 0 means no limit.
+	ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) ([]ScoredMember, error)
+	// ZRank returns the rank of member in ascending score order and whether it exists.
+	ZRank(ctx context.Context, key string, member string) (int64, bool, error)
+	// ZIncrBy adds delta to the score of member and returns the new score.
+	ZIncrBy(ctx context.Context, key string, member string, delta float64, ttl time.Duration) (float64, error)
+	// LPush prepends values to the list at key, applying ttl to the key when
+	// positive. It returns the new length of the list.
+	LPush(ctx context.Context, key string, values []*anypb.Any, ttl time.Duration) (int64, error)
+	// RPop removes and returns the last value of the list at key.
+	RPop(ctx context.Context, key string) (*anypb.Any, bool, error)
+	// BRPop is like RPop but waits up to timeout for a value to arrive.
+	BRPop(ctx context.Context, key string, timeout time.Duration) (*anypb.Any, bool, error)
 }
 
+// Event describes a change of a key in a namespace.
+type Event struct {
+	Type        v1.WatchEvent_Type
+	Key         string
+	ResumeToken string
+	Time        time.Time
+}
+
+// EventLog records and replays key change events per namespace.
+type EventLog interface {
+	// Append records an event. Failures are handled by the implementation and
+	// never fail the write that produced the event.
+	Append(ctx context.Context, namespace string, event *Event)
+	// Watch calls fn for every event recorded after resumeToken, or for new
+	// events only when resumeToken is empty, until ctx is done or fn fails.
+	Watch(ctx context.Context, namespace string, resumeToken string, fn func(*Event) error) error
+}
+
+// keyPrefix is the prefix of every namespaced cache key.
+const keyPrefix = "namespace:"
+
 // NamespacedKey represents a key with a namespace.
 type NamespacedKey struct {
 	Namespace string
 	Key       string
 }
 
-// CacheKey returns the cache key for the NamespacedKey.
+// CacheKey returns the cache key for the NamespacedKey. The namespace is a
+// Redis Cluster hash tag, so every key of a namespace maps to the same slot.
 func (k NamespacedKey) CacheKey() string {
-	return fmt.Sprintf("namespace:%s:%s", k.Namespace, k.Key)
+	return fmt.Sprintf("namespace:{%s}:%s", k.Namespace, k.Key)
+}
+
+// ParseCacheKey parses a cache key produced by NamespacedKey.CacheKey, or by
+// earlier versions without the hash tag. The namespace is assumed not to
+// contain a colon or a closing brace.
+func ParseCacheKey(cacheKey string) (NamespacedKey, bool) {
+	rest, ok := strings.CutPrefix(cacheKey, keyPrefix)
+	if !ok {
+		return NamespacedKey{}, false
+	}
+	separator := ":"
+	if tagged, ok := strings.CutPrefix(rest, "{"); ok {
+		rest, separator = tagged, "}:"
+	}
+	namespace, key, ok := strings.Cut(rest, separator)
+	if !ok {
+		return NamespacedKey{}, false
+	}
+	return NamespacedKey{Namespace: namespace, Key: key}, true
 }
 
 // namespacedBiz is the implementation of NamespacedBiz.
 type namespacedBiz struct {
 	cache     Cache
+	events    EventLog
 	namespace string
 }
 
@@ -49,8 +173,13 @@ type namespacedBiz struct {
 var _ NamespacedBiz = (*namespacedBiz)(nil)
 
 // New creates and returns a new instance of *namespacedBiz.
-func New(cache Cache, namespace string) NamespacedBiz {
-	return &namespacedBiz{cache: cache, namespace: namespace}
+func New(cache Cache, events EventLog, namespace string) NamespacedBiz {
+	return &namespacedBiz{cache: cache, events: events, namespace: namespace}
+}
+
+// publish records a change of key in the namespace event log.
+func (b *namespacedBiz) publish(ctx context.Context, eventType v1.WatchEvent_Type, key string) {
+	b.events.Append(ctx, b.namespace, &Event{Type: eventType, Key: key, Time: time.Now()})
 }
 
 // Set stores a value with the given key and time to live (TTL) in the namespaced cache.
@@ -62,22 +191,295 @@ func (b *namespacedBiz) Set(ctx context.Context, key string, value *anypb.Any, t
 	} else {
 		err = b.cache.Set(ctx, cacheKey, value)
 	}
+	if err == nil {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
 	return &emptypb.Empty{}, err
 }
 
 // Del deletes a value from the namespaced cache by its key.
 func (b *namespacedBiz) Del(ctx context.Context, key string) (*emptypb.Empty, error) {
 	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
-	return &emptypb.Empty{}, b.cache.Del(ctx, cacheKey)
+	if err := b.cache.Del(ctx, cacheKey); err != nil {
+		return &emptypb.Empty{}, err
+	}
+	b.publish(ctx, v1.WatchEvent_DELETE, key)
+	return &emptypb.Empty{}, nil
 }
 
 // Get retrieves a value from the namespaced cache by its key.
-func (b *namespacedBiz) Get(ctx context.Context, key string) (*v1.GetResponse, error) {
+func (b *namespacedBiz) Get(ctx context.Context, key string, slidingExpire *durationpb.Duration) (*v1.GetResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	if slidingExpire != nil {
+		ttl := slidingExpire.AsDuration()
+		if ttl <= 0 {
+			return nil, errors.BadRequest("INVALID_TTL", "sliding expire must be positive")
+		}
+		entry, err := b.cache.GetAndTouch(ctx, cacheKey, ttl)
+		if err != nil {
+			return nil, err
+		}
+		return &v1.GetResponse{Value: entry.Value, Expire: slidingExpire, Version: entry.Version}, nil
+	}
+
+	entry, ttl, err := b.cache.GetWithTTL(ctx, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.GetResponse{Value: entry.Value, Expire: durationpb.New(ttl), Version: entry.Version, Stale: entry.Stale}, nil
+}
+
+// Touch resets the TTL of a key without reading its value.
+func (b *namespacedBiz) Touch(ctx context.Context, key string, ttl *durationpb.Duration) (*v1.TouchResponse, error) {
+	if ttl == nil || ttl.AsDuration() <= 0 {
+		return nil, errors.BadRequest("INVALID_TTL", "ttl must be positive")
+	}
+
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	found, err := b.cache.Touch(ctx, cacheKey, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	return &v1.TouchResponse{Found: found}, nil
+}
+
+// CompareAndSet stores a value only if the current version of the key matches the given version.
+func (b *namespacedBiz) CompareAndSet(ctx context.Context, key string, value *anypb.Any, ttl *durationpb.Duration, version int64) (*v1.CompareAndSetResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	newVersion, ok, err := b.cache.CompareAndSet(ctx, cacheKey, value, version, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
+	return &v1.CompareAndSetResponse{Succeeded: ok, Version: newVersion}, nil
+}
+
+// CompareAndDelete deletes a value only if the current version of the key matches the given version.
+func (b *namespacedBiz) CompareAndDelete(ctx context.Context, key string, version int64) (*v1.CompareAndDeleteResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	ok, err := b.cache.CompareAndDelete(ctx, cacheKey, version)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		b.publish(ctx, v1.WatchEvent_DELETE, key)
+	}
+	return &v1.CompareAndDeleteResponse{Succeeded: ok}, nil
+}
+
+// IncrBy atomically adds delta to the counter stored at key.
+func (b *namespacedBiz) IncrBy(ctx context.Context, key string, delta int64, ttl *durationpb.Duration) (*v1.CounterResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	value, err := b.cache.IncrBy(ctx, cacheKey, delta, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	b.publish(ctx, v1.WatchEvent_SET, key)
+	return &v1.CounterResponse{Value: value}, nil
+}
+
+// IncrWithCap atomically adds delta to the counter stored at key unless the result would exceed cap.
+func (b *namespacedBiz) IncrWithCap(ctx context.Context, key string, delta int64, cap int64, ttl *durationpb.Duration) (*v1.IncrWithCapResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	value, applied, err := b.cache.IncrWithCap(ctx, cacheKey, delta, cap, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	if applied {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
+	return &v1.IncrWithCapResponse{Value: value, Applied: applied}, nil
+}
+
+// Watch streams change events of the watched keys to send until ctx is done.
+func (b *namespacedBiz) Watch(ctx context.Context, rq *v1.WatchRequest, send func(*v1.WatchEvent) error) error {
+	keys := make(map[string]struct{}, len(rq.Keys))
+	for _, key := range rq.Keys {
+		keys[key] = struct{}{}
+	}
+	matches := func(key string) bool {
+		if len(keys) == 0 && rq.Prefix == "" {
+			return true
+		}
+		if _, ok := keys[key]; ok {
+			return true
+		}
+		return rq.Prefix != "" && strings.HasPrefix(key, rq.Prefix)
+	}
+
+	return b.events.Watch(ctx, b.namespace, rq.ResumeToken, func(event *Event) error {
+		if !matches(event.Key) {
+			return nil
+		}
+		return send(&v1.WatchEvent{
+			Type:        event.Type,
+			Key:         event.Key,
+			ResumeToken: event.ResumeToken,
+			Timestamp:   timestamppb.New(event.Time),
+		})
+	})
+}
+
+// HSet sets fields of the hash stored at key.
+func (b *namespacedBiz) HSet(ctx context.Context, key string, fields map[string]*anypb.Any, ttl *durationpb.Duration) (*v1.HSetResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	added, err := b.cache.HSet(ctx, cacheKey, fields, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	b.publish(ctx, v1.WatchEvent_SET, key)
+	return &v1.HSetResponse{Added: added}, nil
+}
+
+// HGet retrieves a field of the hash stored at key.
+func (b *namespacedBiz) HGet(ctx context.Context, key string, field string) (*v1.HGetResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	value, found, err := b.cache.HGet(ctx, cacheKey, field)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.HGetResponse{Value: value, Found: found}, nil
+}
+
+// HDel deletes fields of the hash stored at key.
+func (b *namespacedBiz) HDel(ctx context.Context, key string, fields []string) (*v1.HDelResponse, error) {
 	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
-	value, ttl, err := b.cache.GetWithTTL(ctx, cacheKey)
+	deleted, err := b.cache.HDel(ctx, cacheKey, fields)
 	if err != nil {
 		return nil, err
 	}
+	if deleted > 0 {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
+	return &v1.HDelResponse{Deleted: deleted}, nil
+}
+
+// HGetAll retrieves every field of the hash stored at key.
+func (b *namespacedBiz) HGetAll(ctx context.Context, key string) (*v1.HGetAllResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	fields, err := b.cache.HGetAll(ctx, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.HGetAllResponse{Fields: fields}, nil
+}
+
+// ZAdd adds or updates members of the sorted set stored at key.
+func (b *namespacedBiz) ZAdd(ctx context.Context, key string, members []*v1.ZMember, ttl *durationpb.Duration) (*v1.ZAddResponse, error) {
+	if len(members) == 0 {
+		return nil, errors.BadRequest("INVALID_MEMBERS", "at least one member is required")
+	}
+	scored := make([]ScoredMember, 0, len(members))
+	for _, member := range members {
+		scored = append(scored, ScoredMember{Member: member.Member, Score: member.Score})
+	}
+
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	added, err := b.cache.ZAdd(ctx, cacheKey, scored, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	b.publish(ctx, v1.WatchEvent_SET, key)
+	return &v1.ZAddResponse{Added: added}, nil
+}
+
+// ZRangeByScore retrieves members of the sorted set stored at key within a score range.
+func (b *namespacedBiz) ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) (*v1.ZRangeByScoreResponse, error) {
+	if offset < 0 || count < 0 {
+		return nil, errors.BadRequest("INVALID_RANGE", "offset and count must not be negative")
+	}
+	if min == "" {
+		min = "-inf"
+	}
+	if max == "" {
+		max = "+inf"
+	}
+
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	scored, err := b.cache.ZRangeByScore(ctx, cacheKey, min, max, offset, count)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]*v1.ZMember, 0, len(scored))
+	for _, member := range scored {
+		members = append(members, &v1.ZMember{Member: member.Member, Score: member.Score})
+	}
+	return &v1.ZRangeByScoreResponse{Members: members}, nil
+}
 
-	return &v1.GetResponse{Value: value, Expire: durationpb.New(ttl)}, nil
+// ZRank retrieves the rank of a member of the sorted set stored at key.
+func (b *namespacedBiz) ZRank(ctx context.Context, key string, member string) (*v1.ZRankResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	rank, found, err := b.cache.ZRank(ctx, cacheKey, member)
+	if err != nil {
+		return nil, err
+	}
+	return &v1.ZRankResponse{Rank: rank, Found: found}, nil
+}
+
+// ZIncrBy increments the score of a member of the sorted set stored at key.
+func (b *namespacedBiz) ZIncrBy(ctx context.Context, key string, member string, delta float64, ttl *durationpb.Duration) (*v1.ZIncrByResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	score, err := b.cache.ZIncrBy(ctx, cacheKey, member, delta, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	b.publish(ctx, v1.WatchEvent_SET, key)
+	return &v1.ZIncrByResponse{Score: score}, nil
+}
+
+// LPush prepends values to the list stored at key.
+func (b *namespacedBiz) LPush(ctx context.Context, key string, values []*anypb.Any, ttl *durationpb.Duration) (*v1.LPushResponse, error) {
+	if len(values) == 0 {
+		return nil, errors.BadRequest("INVALID_VALUES", "at least one value is required")
+	}
+
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	length, err := b.cache.LPush(ctx, cacheKey, values, ttl.AsDuration())
+	if err != nil {
+		return nil, err
+	}
+	b.publish(ctx, v1.WatchEvent_SET, key)
+	return &v1.LPushResponse{Length: length}, nil
+}
+
+// RPop removes and returns the last value of the list stored at key.
+func (b *namespacedBiz) RPop(ctx context.Context, key string) (*v1.PopResponse, error) {
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	value, found, err := b.cache.RPop(ctx, cacheKey)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
+	return &v1.PopResponse{Value: value, Found: found}, nil
+}
+
+// BRPop removes and returns the last value of the list stored at key,
+// waiting for one until the timeout or the request deadline elapses.
+func (b *namespacedBiz) BRPop(ctx context.Context, key string, timeout *durationpb.Duration) (*v1.PopResponse, error) {
+	if timeout == nil || timeout.AsDuration() <= 0 {
+		return nil, errors.BadRequest("INVALID_TIMEOUT", "timeout must be positive")
+	}
+	wait := timeout.AsDuration()
+	if deadline, ok := ctx.Deadline(); ok {
+		wait = min(wait, time.Until(deadline))
+	}
+	if wait <= 0 {
+		return &v1.PopResponse{}, nil
+	}
+
+	cacheKey := NamespacedKey{b.namespace, key}.CacheKey()
+	value, found, err := b.cache.BRPop(ctx, cacheKey, wait)
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		b.publish(ctx, v1.WatchEvent_SET, key)
+	}
+	return &v1.PopResponse{Value: value, Found: found}, nil
 }
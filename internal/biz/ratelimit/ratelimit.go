@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v1 "cacheserver/api/cacheserver/v1"
+)
+
+// RateLimitBiz defines the interface for handling rate limit requests.
+type RateLimitBiz interface {
+	Allow(ctx context.Context, rq *v1.RateLimitRequest) (*v1.RateLimitResponse, error)
+}
+
+// Limit describes a rate limit of Limit units per Window.
+type Limit struct {
+	Limit  int64
+	Window time.Duration
+}
+
+// Result is the outcome of a rate limit check.
+type Result struct {
+	Allowed    bool
+	Remaining  int64
+	RetryAfter time.Duration
+	ResetAfter time.Duration
+}
+
+// Limiter defines the interface for rate limiter implementations.
+type Limiter interface {
+	// Allow consumes cost units for key if the limit permits it. When
+	// localPrecheck is set, callers that are clearly over the limit may be
+	// rejected locally without consulting the shared state.
+	Allow(ctx context.Context, key string, limit Limit, cost int64, localPrecheck bool) (*Result, error)
+}
+
+// RateLimitKey represents a rate limit key within a namespace.
+type RateLimitKey struct {
+	Namespace string
+	Key       string
+}
+
+// CacheKey returns the cache key for the RateLimitKey.
+func (k RateLimitKey) CacheKey() string {
+	return fmt.Sprintf("ratelimit:%s:%s", k.Namespace, k.Key)
+}
+
+// rateLimitBiz is the implementation of RateLimitBiz.
+type rateLimitBiz struct {
+	limiter Limiter
+}
+
+// Ensure that *rateLimitBiz implements the RateLimitBiz.
+var _ RateLimitBiz = (*rateLimitBiz)(nil)
+
+// New creates and returns a new instance of *rateLimitBiz.
+func New(limiter Limiter) RateLimitBiz {
+	return &rateLimitBiz{limiter: limiter}
+}
+
+// Allow checks and consumes the rate limit for the given namespace and key.
+func (b *rateLimitBiz) Allow(ctx context.Context, rq *v1.RateLimitRequest) (*v1.RateLimitResponse, error) {
+	if rq.Limit <= 0 {
+		return nil, errors.BadRequest("INVALID_LIMIT", "limit must be positive")
+	}
+	if rq.Window == nil || rq.Window.AsDuration() < time.Millisecond {
+		return nil, errors.BadRequest("INVALID_WINDOW", "window must be at least 1ms")
+	}
+	cost := rq.Cost
+	if cost <= 0 {
+		cost = 1
+	}
+	if cost > rq.Limit {
+		// It could never be allowed, however long the caller waits.
+		return nil, errors.BadRequest("INVALID_COST", "cost must not exceed the limit")
+	}
+
+	cacheKey := RateLimitKey{rq.Namespace, rq.Key}.CacheKey()
+	limit := Limit{Limit: rq.Limit, Window: rq.Window.AsDuration()}
+	result, err := b.limiter.Allow(ctx, cacheKey, limit, cost, rq.LocalPrecheck)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.RateLimitResponse{
+		Allowed:    result.Allowed,
+		Remaining:  result.Remaining,
+		RetryAfter: durationpb.New(result.RetryAfter),
+		ResetAfter: durationpb.New(result.ResetAfter),
+	}, nil
+}
@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	v1 "cacheserver/api/cacheserver/v1"
+)
+
+// allowAll is a Limiter allowing every request.
+type allowAll struct{}
+
+func (allowAll) Allow(context.Context, string, Limit, int64, bool) (*Result, error) {
+	return &Result{Allowed: true}, nil
+}
+
+func TestAllowValidation(t *testing.T) {
+	window := durationpb.New(time.Second)
+	tests := []struct {
+		name       string
+		rq         *v1.RateLimitRequest
+		wantReason string
+	}{
+		{name: "valid", rq: &v1.RateLimitRequest{Limit: 10, Window: window, Cost: 10}},
+		{name: "default cost", rq: &v1.RateLimitRequest{Limit: 1, Window: window}},
+		{name: "no limit", rq: &v1.RateLimitRequest{Window: window}, wantReason: "INVALID_LIMIT"},
+		{name: "sub-millisecond window", rq: &v1.RateLimitRequest{Limit: 10, Window: durationpb.New(time.Microsecond)}, wantReason: "INVALID_WINDOW"},
+		{name: "cost above limit", rq: &v1.RateLimitRequest{Limit: 10, Window: window, Cost: 11}, wantReason: "INVALID_COST"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(allowAll{}).Allow(context.Background(), tt.rq)
+			if got := errors.Reason(err); got != tt.wantReason {
+				t.Fatalf("Allow = %v, want reason %q", err, tt.wantReason)
+			}
+		})
+	}
+}
@@ -2,12 +2,14 @@ package secret
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	v1 "cacheserver/api/cacheserver/v1"
+	"cacheserver/internal/biz/audit"
 )
 
 // SecretBiz defines the interface for handling secret requests.
@@ -15,8 +17,14 @@ type SecretBiz interface {
 	Set(ctx context.Context, rq *v1.SetSecretRequest) (*emptypb.Empty, error)
 	Del(ctx context.Context, rq *v1.DelSecretRequest) (*emptypb.Empty, error)
 	Get(ctx context.Context, rq *v1.GetSecretRequest) (*v1.GetSecretResponse, error)
+	List(ctx context.Context, rq *v1.ListSecretsRequest) (*v1.ListSecretsResponse, error)
+	Undelete(ctx context.Context, rq *v1.UndeleteSecretRequest) (*emptypb.Empty, error)
+	Purge(ctx context.Context, rq *v1.PurgeSecretRequest) (*emptypb.Empty, error)
 }
 
+// ErrSecretExists is returned when restoring a secret whose ID is already in use.
+var ErrSecretExists = errors.New("secret already exists")
+
 // SecretM represents a secret model.
 type SecretM struct {
 	ID          int64
@@ -29,6 +37,15 @@ type SecretM struct {
 	Description string
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
+	DeletedAt   time.Time
+}
+
+// ListOptions holds the filters used when listing secrets.
+type ListOptions struct {
+	UserID      string
+	ShowDeleted bool
+	Offset      int
+	Limit       int
 }
 
 // SecretStore defines the interface for secret storage operations.
@@ -36,19 +53,28 @@ type SecretStore interface {
 	Set(ctx context.Context, key string, value *SecretM) error
 	Get(ctx context.Context, key string) (*SecretM, error)
 	Del(ctx context.Context, key string) error
+	List(ctx context.Context, opts *ListOptions) (int64, []*SecretM, error)
+	// Undelete restores the most recently soft-deleted secret with the given key.
+	Undelete(ctx context.Context, key string) error
+	// Purge permanently removes the secret, including soft-deleted copies.
+	Purge(ctx context.Context, key string) error
 }
 
+// defaultListLimit is used when a list request does not specify a limit.
+const defaultListLimit = 100
+
 // secretBiz is the implementation of SecretBiz.
 type secretBiz struct {
 	store SecretStore
+	audit audit.AuditStore
 }
 
 // Ensure that *secretBiz implements the SecretBiz.
 var _ SecretBiz = (*secretBiz)(nil)
 
 // New creates and returns a new instance of *secretBiz.
-func New(store SecretStore) SecretBiz {
-	return &secretBiz{store: store}
+func New(store SecretStore, audit audit.AuditStore) SecretBiz {
+	return &secretBiz{store: store, audit: audit}
 }
 
 // Set stores a secret in the cache.
@@ -62,17 +88,22 @@ func (b *secretBiz) Set(ctx context.Context, rq *v1.SetSecretRequest) (*emptypb.
 		secret.Expires = time.Now().Add(rq.Expire.AsDuration()).Unix()
 	}
 
-	return &emptypb.Empty{}, b.store.Set(ctx, rq.Key, secret)
+	err := b.store.Set(ctx, rq.Key, secret)
+	b.audit.Record(ctx, audit.NewEvent(ctx, audit.ActionSetSecret, rq.Key, err))
+	return &emptypb.Empty{}, err
 }
 
 // Del deletes a secret from the cache.
 func (b *secretBiz) Del(ctx context.Context, rq *v1.DelSecretRequest) (*emptypb.Empty, error) {
-	return &emptypb.Empty{}, b.store.Del(ctx, rq.Key)
+	err := b.store.Del(ctx, rq.Key)
+	b.audit.Record(ctx, audit.NewEvent(ctx, audit.ActionDelSecret, rq.Key, err))
+	return &emptypb.Empty{}, err
 }
 
 // Get retrieves a secret from the cache.
 func (b *secretBiz) Get(ctx context.Context, rq *v1.GetSecretRequest) (*v1.GetSecretResponse, error) {
 	secret, err := b.store.Get(ctx, rq.Key)
+	b.audit.Record(ctx, audit.NewEvent(ctx, audit.ActionGetSecret, rq.Key, err))
 	if err != nil {
 		return nil, err
 	}
@@ -89,3 +120,54 @@ func (b *secretBiz) Get(ctx context.Context, rq *v1.GetSecretRequest) (*v1.GetSe
 		UpdatedAt:   timestamppb.New(secret.UpdatedAt),
 	}, nil
 }
+
+// List lists secrets, optionally including soft-deleted ones.
+func (b *secretBiz) List(ctx context.Context, rq *v1.ListSecretsRequest) (*v1.ListSecretsResponse, error) {
+	opts := &ListOptions{
+		UserID:      rq.UserID,
+		ShowDeleted: rq.ShowDeleted,
+		Offset:      int(rq.Offset),
+		Limit:       int(rq.Limit),
+	}
+	if opts.Limit <= 0 {
+		opts.Limit = defaultListLimit
+	}
+
+	total, secrets, err := b.store.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &v1.ListSecretsResponse{TotalCount: total, Secrets: make([]*v1.SecretInfo, 0, len(secrets))}
+	for _, secret := range secrets {
+		info := &v1.SecretInfo{
+			UserID:      secret.UserID,
+			Name:        secret.Name,
+			SecretID:    secret.SecretID,
+			Expires:     secret.Expires,
+			Status:      secret.Status,
+			Description: secret.Description,
+			CreatedAt:   timestamppb.New(secret.CreatedAt),
+			UpdatedAt:   timestamppb.New(secret.UpdatedAt),
+		}
+		if !secret.DeletedAt.IsZero() {
+			info.DeletedAt = timestamppb.New(secret.DeletedAt)
+		}
+		resp.Secrets = append(resp.Secrets, info)
+	}
+	return resp, nil
+}
+
+// Undelete restores a soft-deleted secret.
+func (b *secretBiz) Undelete(ctx context.Context, rq *v1.UndeleteSecretRequest) (*emptypb.Empty, error) {
+	err := b.store.Undelete(ctx, rq.Key)
+	b.audit.Record(ctx, audit.NewEvent(ctx, audit.ActionUndeleteSecret, rq.Key, err))
+	return &emptypb.Empty{}, err
+}
+
+// Purge permanently removes a secret.
+func (b *secretBiz) Purge(ctx context.Context, rq *v1.PurgeSecretRequest) (*emptypb.Empty, error) {
+	err := b.store.Purge(ctx, rq.Key)
+	b.audit.Record(ctx, audit.NewEvent(ctx, audit.ActionPurgeSecret, rq.Key, err))
+	return &emptypb.Empty{}, err
+}
@@ -0,0 +1,215 @@
+package data
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+
+	"cacheserver/internal/biz/audit"
+)
+
+const (
+	// auditQueueSize bounds the number of events waiting to be written.
+	auditQueueSize = 10000
+	// auditBatchSize is the maximum number of events written in one insert.
+	auditBatchSize = 100
+	// auditFlushInterval is how long a partial batch may wait before being written.
+	auditFlushInterval = time.Second
+	// auditErrorMaxLen is the maximum length of a stored error message.
+	auditErrorMaxLen = 512
+)
+
+// AuditModel represents the database model for secret audit events.
+type AuditModel struct {
+	ID            uint      `gorm:"primarykey"`
+	Action        string    `gorm:"column:action;type:varchar(32)"`
+	SecretID      string    `gorm:"column:secret_id;type:varchar(64);index"`
+	ClaimedUserID string    `gorm:"column:claimed_user_id;type:varchar(64);index"`
+	Peer          string    `gorm:"column:peer;type:varchar(128)"`
+	Outcome       string    `gorm:"column:outcome;type:varchar(16)"`
+	Error         string    `gorm:"column:error;type:varchar(512)"`
+	TraceID       string    `gorm:"column:trace_id;type:varchar(64)"`
+	CreatedAt     time.Time `gorm:"column:created_at;index"`
+}
+
+// TableName returns the table name for AuditModel.
+func (AuditModel) TableName() string {
+	return "secret_audit"
+}
+
+// legacyAuditUserColumn and legacyAuditUserIndex are the column and index of
+// earlier versions holding the claimed caller identity as if it was verified.
+const (
+	legacyAuditUserColumn = "user_id"
+	legacyAuditUserIndex  = "idx_secret_audit_user_id"
+)
+
+// migrateAudit migrates the audit table, renaming the legacy user_id column
+// to claimed_user_id so that existing events keep their claimed identity.
+func migrateAudit(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasTable(&AuditModel{}) && migrator.HasColumn(&AuditModel{}, legacyAuditUserColumn) {
+		if migrator.HasIndex(&AuditModel{}, legacyAuditUserIndex) {
+			if err := migrator.DropIndex(&AuditModel{}, legacyAuditUserIndex); err != nil {
+				return err
+			}
+		}
+		if err := migrator.RenameColumn(&AuditModel{}, legacyAuditUserColumn, "claimed_user_id"); err != nil {
+			return err
+		}
+	}
+	return db.AutoMigrate(&AuditModel{})
+}
+
+// auditStore implements the audit.AuditStore interface. Events are written to
+// MySQL asynchronously through a bounded queue; when the queue is full new
+// events are dropped rather than blocking the request path.
+type auditStore struct {
+	db    *gorm.DB
+	queue chan *AuditModel
+	wg    sync.WaitGroup
+	log   *log.Helper
+
+	// mu guards closed: the queue is only closed once no Record can send on it.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// NewAuditStore creates an audit store and starts its background writer.
+func NewAuditStore(data *Data, logger log.Logger) (*auditStore, func()) {
+	s := &auditStore{
+		db:    data.DB(),
+		queue: make(chan *AuditModel, auditQueueSize),
+		log:   log.NewHelper(logger),
+	}
+
+	s.wg.Add(1)
+	go s.run()
+
+	cleanup := func() {
+		s.log.Info("flushing pending audit events")
+		s.mu.Lock()
+		s.closed = true
+		close(s.queue)
+		s.mu.Unlock()
+		s.wg.Wait()
+	}
+	return s, cleanup
+}
+
+// Record enqueues an audit event for asynchronous persistence.
+func (s *auditStore) Record(_ context.Context, event *audit.EventM) {
+	model := &AuditModel{
+		Action:        event.Action,
+		SecretID:      event.SecretID,
+		ClaimedUserID: event.ClaimedUserID,
+		Peer:          event.Peer,
+		Outcome:       event.Outcome,
+		Error:         truncate(event.Error, auditErrorMaxLen),
+		TraceID:       event.TraceID,
+		CreatedAt:     event.CreatedAt,
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		s.log.Warnf("audit store is closed, dropping event: action=%s secret_id=%s", event.Action, event.SecretID)
+		return
+	}
+	select {
+	case s.queue <- model:
+	default:
+		s.log.Warnf("audit queue is full, dropping event: action=%s secret_id=%s", event.Action, event.SecretID)
+	}
+}
+
+// List retrieves audit events matching the given filters, newest first.
+func (s *auditStore) List(ctx context.Context, opts *audit.ListOptions) (int64, []*audit.EventM, error) {
+	query := s.db.WithContext(ctx).Model(&AuditModel{})
+	if opts.SecretID != "" {
+		query = query.Where("secret_id = ?", opts.SecretID)
+	}
+	if opts.ClaimedUserID != "" {
+		query = query.Where("claimed_user_id = ?", opts.ClaimedUserID)
+	}
+	if !opts.Start.IsZero() {
+		query = query.Where("created_at >= ?", opts.Start)
+	}
+	if !opts.End.IsZero() {
+		query = query.Where("created_at < ?", opts.End)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var models []*AuditModel
+	if err := query.Order("id DESC").Offset(opts.Offset).Limit(opts.Limit).Find(&models).Error; err != nil {
+		return 0, nil, err
+	}
+
+	events := make([]*audit.EventM, 0, len(models))
+	for _, model := range models {
+		events = append(events, &audit.EventM{
+			ID:            int64(model.ID),
+			Action:        model.Action,
+			SecretID:      model.SecretID,
+			ClaimedUserID: model.ClaimedUserID,
+			Peer:          model.Peer,
+			Outcome:       model.Outcome,
+			Error:         model.Error,
+			TraceID:       model.TraceID,
+			CreatedAt:     model.CreatedAt,
+		})
+	}
+	return total, events, nil
+}
+
+// run drains the queue, writing events in batches until the queue is closed.
+func (s *auditStore) run() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*AuditModel, 0, auditBatchSize)
+	for {
+		select {
+		case model, ok := <-s.queue:
+			if !ok {
+				s.flush(batch)
+				return
+			}
+			batch = append(batch, model)
+			if len(batch) >= auditBatchSize {
+				s.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			s.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+// flush writes a batch of events to the database.
+func (s *auditStore) flush(batch []*AuditModel) {
+	if len(batch) == 0 {
+		return
+	}
+	if err := s.db.Create(&batch).Error; err != nil {
+		s.log.Errorf("failed to write %d audit events: %v", len(batch), err)
+	}
+}
+
+// truncate shortens s to at most n bytes.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
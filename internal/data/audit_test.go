@@ -0,0 +1,110 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"cacheserver/internal/biz/audit"
+	"cacheserver/internal/conf"
+)
+
+// newTestAuditDB returns an in-memory SQLite database.
+func newTestAuditDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := openDatabase(&conf.Data_Database{Driver: databaseDriverSQLite, Source: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	t.Cleanup(func() { _ = closeDatabase(db) })
+	return db
+}
+
+// listClaimedBy returns the number of audit events claimed by a user.
+func listClaimedBy(t *testing.T, s *auditStore, userID string) int64 {
+	t.Helper()
+
+	total, events, err := s.List(context.Background(), &audit.ListOptions{ClaimedUserID: userID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(events)) != total {
+		t.Fatalf("List returned %d events, want %d", len(events), total)
+	}
+	return total
+}
+
+func TestAuditStoreQueueFull(t *testing.T) {
+	s := &auditStore{queue: make(chan *AuditModel, 1), log: log.NewHelper(log.DefaultLogger)}
+	event := &audit.EventM{Action: audit.ActionGetSecret, SecretID: "s1"}
+
+	// Record never blocks: the event that does not fit is dropped.
+	s.Record(context.Background(), event)
+	s.Record(context.Background(), event)
+	if len(s.queue) != 1 {
+		t.Fatalf("queue holds %d events, want 1", len(s.queue))
+	}
+}
+
+func TestAuditStoreClose(t *testing.T) {
+	db := newTestAuditDB(t)
+	if err := migrateAudit(db); err != nil {
+		t.Fatal(err)
+	}
+	s, cleanup := NewAuditStore(&Data{db: db}, log.DefaultLogger)
+	ctx := context.Background()
+
+	for _, user := range []string{"alice", "alice", "bob"} {
+		s.Record(ctx, &audit.EventM{Action: audit.ActionGetSecret, SecretID: "s1", ClaimedUserID: user, CreatedAt: time.Now()})
+	}
+	// Closing flushes the queued events; later ones are dropped.
+	cleanup()
+	s.Record(ctx, &audit.EventM{Action: audit.ActionGetSecret, SecretID: "s1", ClaimedUserID: "alice", CreatedAt: time.Now()})
+
+	if n := listClaimedBy(t, s, "alice"); n != 2 {
+		t.Fatalf("%d events claimed by alice, want 2", n)
+	}
+	if n := listClaimedBy(t, s, "bob"); n != 1 {
+		t.Fatalf("%d events claimed by bob, want 1", n)
+	}
+}
+
+// legacyAuditModel is the audit table of earlier versions.
+type legacyAuditModel struct {
+	ID     uint   `gorm:"primarykey"`
+	UserID string `gorm:"column:user_id;type:varchar(64);index"`
+}
+
+func (legacyAuditModel) TableName() string {
+	return "secret_audit"
+}
+
+func TestMigrateAuditLegacyColumn(t *testing.T) {
+	db := newTestAuditDB(t)
+	if err := db.AutoMigrate(&legacyAuditModel{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&legacyAuditModel{UserID: "bob"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// The identity of existing events is kept as a claim.
+	for range 2 {
+		if err := migrateAudit(db); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if db.Migrator().HasColumn(&AuditModel{}, legacyAuditUserColumn) {
+		t.Fatalf("column %s still exists", legacyAuditUserColumn)
+	}
+	s := &auditStore{db: db}
+	if n := listClaimedBy(t, s, "bob"); n != 1 {
+		t.Fatalf("%d events claimed by bob, want 1", n)
+	}
+}
@@ -3,72 +3,377 @@ package data
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
+	"github.com/go-kratos/kratos/v2/errors"
 	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	"cacheserver/internal/biz/namespaced"
 	"cacheserver/pkg/cache"
+	"cacheserver/pkg/cache/store"
+	redisstore "cacheserver/pkg/cache/store/redis"
 )
 
+// compareAndSetScript replaces KEYS[1] with ARGV[2] if the version of the
+// stored entry equals ARGV[1] ("0" meaning the key must not exist). Entries
+// written before versioning have version ARGV[4]. ARGV[3] is the TTL in
+// milliseconds, 0 for none.
+var compareAndSetScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then
+  if ARGV[1] ~= '0' then return 0 end
+else
+  local ok, entry = pcall(cjson.decode, current)
+  if not ok or type(entry) ~= 'table' then return 0 end
+  local version = entry['version'] or ARGV[4]
+  if tostring(version) ~= ARGV[1] then return 0 end
+end
+if tonumber(ARGV[3]) > 0 then
+  redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+else
+  redis.call('SET', KEYS[1], ARGV[2])
+end
+return 1
+`)
+
+// compareAndDeleteScript deletes KEYS[1] if the version of the stored entry
+// equals ARGV[1]. Entries written before versioning have version ARGV[2].
+var compareAndDeleteScript = redis.NewScript(`
+local current = redis.call('GET', KEYS[1])
+if not current then return 0 end
+local ok, entry = pcall(cjson.decode, current)
+if not ok or type(entry) ~= 'table' then return 0 end
+local version = entry['version'] or ARGV[2]
+if tostring(version) ~= ARGV[1] then return 0 end
+redis.call('DEL', KEYS[1])
+return 1
+`)
+
+// incrByScript adds ARGV[1] to the counter at KEYS[1] and, if ARGV[2] is
+// positive and the key has no expiration, sets its TTL in milliseconds.
+var incrByScript = redis.NewScript(`
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return value
+`)
+
+// incrWithCapScript is like incrByScript but leaves the counter unchanged if
+// the result would exceed ARGV[3]. It returns {value, applied}.
+var incrWithCapScript = redis.NewScript(`
+local raw = redis.call('GET', KEYS[1])
+local current = 0
+if raw then
+  current = tonumber(raw)
+  if not current then return redis.error_reply('ERR value is not an integer or out of range') end
+end
+if current + tonumber(ARGV[1]) > tonumber(ARGV[3]) then return {current, 0} end
+local value = redis.call('INCRBY', KEYS[1], ARGV[1])
+if tonumber(ARGV[2]) > 0 and redis.call('PTTL', KEYS[1]) == -1 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return {value, 1}
+`)
+
+// namespacedEntry is the stored representation of a namespaced value. The
+// version is encoded as a string so Lua scripts can compare it without
+// losing precision.
+type namespacedEntry struct {
+	Version int64      `json:"version,string"`
+	Value   *anypb.Any `json:"value"`
+}
+
+const (
+	// versionKey is the Redis counter from which entry versions are allocated.
+	versionKey = "version:namespaced"
+	// versionBlockSize is the number of versions reserved at once.
+	versionBlockSize = 100
+	// legacyVersion is the version of the entries written before versioning,
+	// below every allocated version.
+	legacyVersion = 1
+)
+
+// versionAllocator hands out entry versions reserved in blocks from a Redis
+// counter, so that they are unique across replicas and clock steps. Versions
+// only increase within a replica. The counter is seeded with the current
+// time in microseconds, so that the versions allocated after Redis lost it
+// are above the ones still held by the SQL level.
+type versionAllocator struct {
+	rdb redis.UniversalClient
+
+	mu         sync.Mutex
+	next, last int64
+}
+
+// Next returns a new entry version.
+func (a *versionAllocator) Next(ctx context.Context) (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.next == 0 || a.next > a.last {
+		var last *redis.IntCmd
+		_, err := a.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.SetNX(ctx, versionKey, time.Now().UnixMicro(), 0)
+			last = pipe.IncrBy(ctx, versionKey, versionBlockSize)
+			return nil
+		})
+		if err != nil {
+			return 0, fmt.Errorf("allocate entry versions: %w", err)
+		}
+		a.last = last.Val()
+		a.next = a.last - versionBlockSize + 1
+	}
+
+	version := a.next
+	a.next++
+	return version, nil
+}
+
+// entryCodec is the cache.Codec of namespaced entries.
+type entryCodec struct{}
+
+// Marshal serializes an entry with its version. Counters, with version 0,
+// are stored as plain integers like in Redis.
+func (entryCodec) Marshal(entry *namespaced.Entry) ([]byte, error) {
+	if entry.Version == 0 {
+		var counter wrapperspb.Int64Value
+		if entry.Value.MessageIs(&counter) && entry.Value.UnmarshalTo(&counter) == nil {
+			return strconv.AppendInt(nil, counter.Value, 10), nil
+		}
+	}
+	return json.Marshal(&namespacedEntry{Version: entry.Version, Value: entry.Value})
+}
+
+// Unmarshal deserializes a stored value. Counters are stored as plain
+// integers and are returned as an Int64Value with version 0, so that they can
+// never be compared and set. Values written before versioning was introduced
+// are a bare Any and are returned with legacyVersion.
+func (entryCodec) Unmarshal(data []byte) (*namespaced.Entry, error) {
+	if n, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		value, err := anypb.New(wrapperspb.Int64(n))
+		if err != nil {
+			return nil, err
+		}
+		return &namespaced.Entry{Value: value}, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	if _, ok := fields["version"]; !ok {
+		value := &anypb.Any{}
+		if err := json.Unmarshal(data, value); err != nil {
+			return nil, err
+		}
+		return &namespaced.Entry{Value: value, Version: legacyVersion}, nil
+	}
+
+	var entry namespacedEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &namespaced.Entry{Value: entry.Value, Version: entry.Version}, nil
+}
+
+// redisLevel is the index of the Redis level in the namespaced chains.
+const redisLevel = 1
+
 // namespacedCache implements the namespaced.Cache interface using chain cache.
+// Operations running directly on Redis invalidate the local level through
+// the chain, which discards the backfills read before them, and, for
+// durable namespaces, are written through to the SQL level.
 type namespacedCache struct {
-	chain *cache.ChainCache[any]
-	log   *log.Helper
+	chain    *cache.ChainCache[*namespaced.Entry]
+	local    cache.Cache[*namespaced.Entry]
+	hashes   cache.Cache[localHash]
+	redis    *redisstore.RedisStore
+	rdb      redis.UniversalClient
+	blocking redis.UniversalClient
+	versions *versionAllocator
+	policies map[string]namespacePolicy
+	log      *log.Helper
+
+	// hashGens orders the writes of keys against the hashes copied into the
+	// local level, so that a hash read before a write is not cached after it.
+	hashGens *cache.Generations
+
+	// chain ending with the SQL level of the durable namespaces, nil if
+	// there are none
+	durableChain *cache.ChainCache[*namespaced.Entry]
+	durable      cache.Cache[*namespaced.Entry]
+	isDurable    func(key string) bool
+	touches      *durableTouches
 }
 
 // Set stores a value in the cache.
 func (c *namespacedCache) Set(ctx context.Context, key string, value *anypb.Any) error {
-	// Serialize protobuf to JSON for storage
-	data, err := json.Marshal(value)
+	version, err := c.versions.Next(ctx)
 	if err != nil {
 		return err
 	}
-	return c.chain.Set(ctx, key, string(data))
+	defer c.hashGens.Advance(key)
+	return c.chainFor(key).Set(ctx, key, &namespaced.Entry{Value: value, Version: version})
 }
 
 // SetWithTTL stores a value in the cache with a TTL.
 func (c *namespacedCache) SetWithTTL(ctx context.Context, key string, value *anypb.Any, ttl time.Duration) error {
-	data, err := json.Marshal(value)
+	version, err := c.versions.Next(ctx)
 	if err != nil {
 		return err
 	}
-	return c.chain.SetWithTTL(ctx, key, string(data), ttl)
+	defer c.hashGens.Advance(key)
+	return c.chainFor(key).SetWithTTL(ctx, key, &namespaced.Entry{Value: value, Version: version}, ttl)
 }
 
 // Get retrieves a value from the cache.
-func (c *namespacedCache) Get(ctx context.Context, key string) (*anypb.Any, error) {
-	result, err := c.chain.Get(ctx, key)
+func (c *namespacedCache) Get(ctx context.Context, key string) (*namespaced.Entry, error) {
+	entry, _, err := c.GetWithTTL(ctx, key)
+	return entry, err
+}
+
+// GetWithTTL retrieves a value and its TTL from the cache.
+func (c *namespacedCache) GetWithTTL(ctx context.Context, key string) (*namespaced.Entry, time.Duration, error) {
+	entry, lookup, err := c.chainFor(key).Lookup(ctx, key)
+	if errors.Is(err, cache.ErrTypeMismatch) || isWrongType(err) {
+		return nil, 0, errWrongType
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	entry.Stale = lookup.Stale
+	return entry, lookup.TTL, nil
+}
+
+// Del removes a value from the cache.
+func (c *namespacedCache) Del(ctx context.Context, key string) error {
+	defer c.hashGens.Advance(key)
+	return c.chainFor(key).Del(ctx, key)
+}
+
+// GetAndTouch reads the value from Redis with GETEX, resetting its TTL, and
+// stores it in the local level with the same TTL. The local level cannot
+// serve these reads since Redis must see every access. A key of a durable
+// namespace missing from Redis is read through the chain and restored.
+func (c *namespacedCache) GetAndTouch(ctx context.Context, key string, ttl time.Duration) (*namespaced.Entry, error) {
+	data, err := c.rdb.GetEx(ctx, key, ttl).Result()
+	if errors.Is(err, redis.Nil) && c.durableChain != nil && c.isDurable(key) {
+		data, err = c.getExDurable(ctx, key, ttl)
+	}
+	if errors.Is(err, redis.Nil) {
+		return nil, store.ErrKeyNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	value := &anypb.Any{}
-	if str, ok := result.(string); ok {
-		if err := json.Unmarshal([]byte(str), value); err != nil {
-			return nil, err
-		}
+	entry, err := entryCodec{}.Unmarshal([]byte(data))
+	if err != nil {
+		return nil, err
 	}
-	return value, nil
+	if err := c.chainFor(key).Invalidate(ctx, key, redisLevel); err != nil {
+		return nil, err
+	}
+	if err := c.local.SetWithTTL(ctx, key, entry, ttl); err != nil {
+		c.log.Warnf("failed to refresh local cache after touch: %v", err)
+		_ = c.local.Del(ctx, key)
+	}
+	if err := c.touchDurable(ctx, key, entry.Version, ttl); err != nil {
+		return nil, err
+	}
+	return entry, nil
 }
 
-// GetWithTTL retrieves a value and its TTL from the cache.
-func (c *namespacedCache) GetWithTTL(ctx context.Context, key string) (*anypb.Any, time.Duration, error) {
-	result, ttl, err := c.chain.GetWithTTL(ctx, key)
+// Touch resets the TTL of the key in Redis and invalidates the local level,
+// which is repopulated with the new TTL on the next read.
+func (c *namespacedCache) Touch(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	found, err := c.rdb.PExpire(ctx, key, ttl).Result()
 	if err != nil {
-		return nil, 0, err
+		return false, err
 	}
+	return found, c.invalidate(ctx, key)
+}
 
-	value := &anypb.Any{}
-	if str, ok := result.(string); ok {
-		if err := json.Unmarshal([]byte(str), value); err != nil {
-			return nil, 0, err
-		}
+// CompareAndSet atomically replaces the value in Redis if its version matches.
+// The local level is updated on success and invalidated otherwise.
+func (c *namespacedCache) CompareAndSet(ctx context.Context, key string, value *anypb.Any, version int64, ttl time.Duration) (int64, bool, error) {
+	newVersion, err := c.versions.Next(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	entry := &namespaced.Entry{Value: value, Version: newVersion}
+	data, err := entryCodec{}.Marshal(entry)
+	if err != nil {
+		return 0, false, err
+	}
+	c.redis.MarkWritten(key)
+
+	swapped, err := compareAndSetScript.Run(ctx, c.rdb, []string{key},
+		strconv.FormatInt(version, 10), data, ttl.Milliseconds(), legacyVersion).Bool()
+	if err != nil {
+		return 0, false, err
+	}
+	if !swapped {
+		return 0, false, c.chainFor(key).Invalidate(ctx, key, redisLevel)
+	}
+	c.hashGens.Advance(key)
+	if err := c.chainFor(key).Invalidate(ctx, key, redisLevel); err != nil {
+		return 0, false, err
 	}
-	return value, ttl, nil
+
+	if ttl > 0 {
+		err = c.local.SetWithTTL(ctx, key, entry, ttl)
+	} else {
+		err = c.local.Set(ctx, key, entry)
+	}
+	if err != nil {
+		// The swap has happened; a stale local copy is the only risk.
+		c.log.Warnf("failed to update local cache after compare-and-set: %v", err)
+		_ = c.local.Del(ctx, key)
+	}
+	return entry.Version, true, c.syncDurable(ctx, key)
 }
 
-// Del removes a value from the cache.
-func (c *namespacedCache) Del(ctx context.Context, key string) error {
-	return c.chain.Del(ctx, key)
+// CompareAndDelete atomically deletes the value in Redis if its version
+// matches, and invalidates the local level.
+func (c *namespacedCache) CompareAndDelete(ctx context.Context, key string, version int64) (bool, error) {
+	c.redis.MarkWritten(key)
+	deleted, err := compareAndDeleteScript.Run(ctx, c.rdb, []string{key},
+		strconv.FormatInt(version, 10), legacyVersion).Bool()
+	if err != nil {
+		return false, err
+	}
+	return deleted, c.invalidate(ctx, key)
+}
+
+// IncrBy atomically increments the counter in Redis in a single round trip
+// and invalidates the local level.
+func (c *namespacedCache) IncrBy(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	c.redis.MarkWritten(key)
+	value, err := incrByScript.Run(ctx, c.rdb, []string{key}, delta, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, err
+	}
+	return value, c.invalidate(ctx, key)
+}
+
+// IncrWithCap atomically increments the counter in Redis unless the result
+// would exceed limit, and invalidates the local level.
+func (c *namespacedCache) IncrWithCap(ctx context.Context, key string, delta int64, limit int64, ttl time.Duration) (int64, bool, error) {
+	c.redis.MarkWritten(key)
+	result, err := incrWithCapScript.Run(ctx, c.rdb, []string{key}, delta, ttl.Milliseconds(), limit).Int64Slice()
+	if err != nil {
+		return 0, false, err
+	}
+	if len(result) != 2 {
+		return 0, false, fmt.Errorf("unexpected reply from incr-with-cap script: %v", result)
+	}
+	return result[0], result[1] == 1, c.invalidate(ctx, key)
 }
@@ -0,0 +1,220 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache/store"
+	memorystore "cacheserver/pkg/cache/store/memory"
+)
+
+// newTestData returns a Data backed by an in-memory Redis and local stores.
+func newTestData(t *testing.T) (*Data, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	local := memorystore.NewMemory(memorystore.Options{MaxSize: 1 << 20, Sizer: localSize})
+	t.Cleanup(func() {
+		local.Close()
+		_ = rdb.Close()
+	})
+	return &Data{rdb: rdb, namespacedLocal: local, secretLocal: local}, mr
+}
+
+// newTestNamespacedCache returns a namespaced cache configured with c.
+func newTestNamespacedCache(t *testing.T, data *Data, c *conf.Data) *namespacedCache {
+	t.Helper()
+
+	nc, cleanup, err := NewNamespacedCache(c, data, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+	return nc
+}
+
+func mustAny(t *testing.T, value string) *anypb.Any {
+	t.Helper()
+
+	a, err := anypb.New(wrapperspb.String(value))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNamespacedCacheCompareAndSet(t *testing.T) {
+	data, mr := newTestData(t)
+	c := newTestNamespacedCache(t, data, &conf.Data{})
+	ctx := context.Background()
+	key := "namespace:{test}:key"
+
+	created, ok, err := c.CompareAndSet(ctx, key, mustAny(t, "a"), 0, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("CompareAndSet(0) = %v, %v", ok, err)
+	}
+	if _, ok, _ := c.CompareAndSet(ctx, key, mustAny(t, "b"), 0, time.Minute); ok {
+		t.Fatal("CompareAndSet(0) succeeded on an existing key")
+	}
+
+	updated, ok, err := c.CompareAndSet(ctx, key, mustAny(t, "c"), created, time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("CompareAndSet(%d) = %v, %v", created, ok, err)
+	}
+	if updated == created {
+		t.Fatalf("CompareAndSet returned the previous version %d", created)
+	}
+	if _, ok, _ := c.CompareAndSet(ctx, key, mustAny(t, "d"), created, time.Minute); ok {
+		t.Fatal("CompareAndSet succeeded with a stale version")
+	}
+
+	// Versions are allocated in Redis: a replica starting from the same
+	// clock never hands out the same ones.
+	other := &versionAllocator{rdb: data.RDB()}
+	for range versionBlockSize + 1 {
+		version, err := other.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if version == created || version == updated {
+			t.Fatalf("version %d allocated twice", version)
+		}
+	}
+
+	// Entries written before versioning can be compared and set with the
+	// legacy version.
+	legacy, err := json.Marshal(mustAny(t, "legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mr.Set("namespace:{test}:legacy", string(legacy)); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := c.Get(ctx, "namespace:{test}:legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Version != legacyVersion {
+		t.Fatalf("legacy entry version = %d, want %d", entry.Version, legacyVersion)
+	}
+	if _, ok, err := c.CompareAndSet(ctx, "namespace:{test}:legacy", mustAny(t, "e"), entry.Version, 0); err != nil || !ok {
+		t.Fatalf("CompareAndSet(legacy) = %v, %v", ok, err)
+	}
+}
+
+// gatedStore is a store whose writes of key, once set, wait at a gate:
+// entered is closed once a write reached it and release lets it through.
+type gatedStore struct {
+	store.Store
+	key     any
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func (g *gatedStore) Set(ctx context.Context, key any, value any) error {
+	g.wait(key)
+	return g.Store.Set(ctx, key, value)
+}
+
+func (g *gatedStore) SetWithTTL(ctx context.Context, key any, value any, ttl time.Duration) error {
+	g.wait(key)
+	return g.Store.SetWithTTL(ctx, key, value, ttl)
+}
+
+func (g *gatedStore) wait(key any) {
+	if key == g.key {
+		g.once.Do(func() { close(g.entered) })
+		<-g.release
+	}
+}
+
+func TestNamespacedCacheBackfillRacingCompareAndDelete(t *testing.T) {
+	data, _ := newTestData(t)
+	key := "namespace:{test}:key"
+	local := &gatedStore{Store: data.namespacedLocal, entered: make(chan struct{}), release: make(chan struct{})}
+	data.namespacedLocal = local
+	c := newTestNamespacedCache(t, data, &conf.Data{})
+	ctx := context.Background()
+
+	version, _, err := c.CompareAndSet(ctx, key, mustAny(t, "v"), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.local.Del(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+
+	// The read from Redis is copied into the local level in the background,
+	// where it is held while the key is deleted.
+	local.key = key
+	if _, err := c.Get(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	<-local.entered
+	if deleted, err := c.CompareAndDelete(ctx, key, version); err != nil || !deleted {
+		t.Fatalf("CompareAndDelete = %v, %v", deleted, err)
+	}
+	close(local.release)
+	if err := c.chain.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get(ctx, key); !errors.Is(err, store.ErrKeyNotFound) {
+		t.Fatalf("Get after CompareAndDelete = %v, want %v", err, store.ErrKeyNotFound)
+	}
+}
+
+func TestNamespacedCacheSoftTTL(t *testing.T) {
+	data, _ := newTestData(t)
+	cfg := &conf.Data{Cache: &conf.Data_Cache{SoftTtl: durationpb.New(time.Millisecond)}}
+	c := newTestNamespacedCache(t, data, cfg)
+	ctx := context.Background()
+	key := "namespace:{test}:key"
+
+	// Another replica, with its own local level, shares Redis.
+	local := memorystore.NewMemory(memorystore.Options{MaxSize: 1 << 20, Sizer: localSize})
+	t.Cleanup(local.Close)
+	other := newTestNamespacedCache(t, &Data{rdb: data.RDB(), namespacedLocal: local, secretLocal: local}, cfg)
+
+	if err := c.Set(ctx, key, mustAny(t, "old")); err != nil {
+		t.Fatal(err)
+	}
+	if err := other.Set(ctx, key, mustAny(t, "new")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The local copy is served until a read past the soft TTL refreshes it
+	// from Redis in the background.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		entry, err := c.Get(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var value wrapperspb.StringValue
+		if err := entry.Value.UnmarshalTo(&value); err != nil {
+			t.Fatal(err)
+		}
+		if value.GetValue() == "new" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("local value never refreshed from Redis")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
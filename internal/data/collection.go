@@ -0,0 +1,289 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+)
+
+// Sorted sets and lists are read and written in Redis only. Writes still
+// invalidate the local level so that it never serves a value the key no
+// longer holds.
+
+// blockingReplyMargin is the part of the request deadline left for the
+// reply of a blocking command to arrive.
+const blockingReplyMargin = 50 * time.Millisecond
+
+// errQuotaExceeded is returned when a write would grow a sorted set or a list
+// beyond the quota of its namespace.
+var errQuotaExceeded = errors.New(http.StatusTooManyRequests, "QUOTA_EXCEEDED", "the collection would exceed the length quota of its namespace")
+
+// zaddScript adds members to the sorted set KEYS[1], unless the set would
+// grow beyond its maximum length. ARGV[1] max length (0 for no limit),
+// ARGV[2] TTL in ms (0 for none), then score/member pairs from ARGV[3].
+var zaddScript = redis.NewScript(`
+local max = tonumber(ARGV[1])
+if max > 0 then
+  local new, seen = 0, {}
+  for i = 4, #ARGV, 2 do
+    if not seen[ARGV[i]] and not redis.call('ZSCORE', KEYS[1], ARGV[i]) then new = new + 1 end
+    seen[ARGV[i]] = true
+  end
+  if new > 0 and redis.call('ZCARD', KEYS[1]) + new > max then return redis.error_reply('QUOTA exceeded') end
+end
+local added = 0
+for i = 3, #ARGV, 2 do
+  added = added + redis.call('ZADD', KEYS[1], ARGV[i], ARGV[i + 1])
+end
+if tonumber(ARGV[2]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+return added
+`)
+
+// zincrbyScript adds ARGV[3] to the score of the member ARGV[4] of the
+// sorted set KEYS[1], unless adding the member would grow the set beyond
+// ARGV[1] members (0 for no limit). ARGV[2] is the TTL in milliseconds, 0
+// for none. The score is returned as a string.
+var zincrbyScript = redis.NewScript(`
+local max = tonumber(ARGV[1])
+if max > 0 and not redis.call('ZSCORE', KEYS[1], ARGV[4]) and redis.call('ZCARD', KEYS[1]) >= max then
+  return redis.error_reply('QUOTA exceeded')
+end
+local score = redis.call('ZINCRBY', KEYS[1], ARGV[3], ARGV[4])
+if tonumber(ARGV[2]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+return score
+`)
+
+// lpushScript prepends the values ARGV[3].. to the list KEYS[1], unless the
+// list would grow beyond ARGV[1] values (0 for no limit). ARGV[2] is the TTL
+// in milliseconds, 0 for none. It returns the length of the list.
+var lpushScript = redis.NewScript(`
+local max = tonumber(ARGV[1])
+if max > 0 and redis.call('LLEN', KEYS[1]) + #ARGV - 2 > max then
+  return redis.error_reply('QUOTA exceeded')
+end
+local length = 0
+for i = 3, #ARGV do
+  length = redis.call('LPUSH', KEYS[1], ARGV[i])
+end
+if tonumber(ARGV[2]) > 0 then redis.call('PEXPIRE', KEYS[1], ARGV[2]) end
+return length
+`)
+
+// isQuotaExceeded reports whether err is the error replied by a script
+// refusing to exceed a quota.
+func isQuotaExceeded(err error) bool {
+	var redisErr redis.Error
+	return errors.As(err, &redisErr) && strings.HasPrefix(redisErr.Error(), "QUOTA")
+}
+
+// scriptError maps the errors replied by the collection scripts.
+func scriptError(err error) error {
+	switch {
+	case isQuotaExceeded(err):
+		return errQuotaExceeded
+	case isWrongType(err):
+		return errWrongType
+	default:
+		return err
+	}
+}
+
+// namespacePolicy is the TTL and quota policy of a namespace for its sorted
+// sets and lists.
+type namespacePolicy struct {
+	defaultTTL time.Duration
+	maxTTL     time.Duration
+	maxLength  int64
+}
+
+// newNamespacePolicies converts the configured policies by namespace.
+func newNamespacePolicies(c map[string]*conf.Data_Namespace) map[string]namespacePolicy {
+	policies := make(map[string]namespacePolicy, len(c))
+	for namespace, policy := range c {
+		policies[namespace] = namespacePolicy{
+			defaultTTL: policy.DefaultTtl.AsDuration(),
+			maxTTL:     policy.MaxTtl.AsDuration(),
+			maxLength:  policy.MaxLength,
+		}
+	}
+	return policies
+}
+
+// ttl returns the TTL of a write requested with ttl, 0 meaning none.
+func (p namespacePolicy) ttl(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = p.defaultTTL
+	}
+	if p.maxTTL > 0 && (ttl <= 0 || ttl > p.maxTTL) {
+		ttl = p.maxTTL
+	}
+	return ttl
+}
+
+// policy returns the policy of the namespace of a key.
+func (c *namespacedCache) policy(key string) namespacePolicy {
+	parsed, ok := namespaced.ParseCacheKey(key)
+	if !ok {
+		return namespacePolicy{}
+	}
+	return c.policies[parsed.Namespace]
+}
+
+// ZAdd adds or updates members of the sorted set in Redis, within the quota
+// of its namespace.
+func (c *namespacedCache) ZAdd(ctx context.Context, key string, members []namespaced.ScoredMember, ttl time.Duration) (int64, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return 0, err
+	}
+
+	policy := c.policy(key)
+	args := make([]any, 0, 2+2*len(members))
+	args = append(args, policy.maxLength, policy.ttl(ttl).Milliseconds())
+	for _, member := range members {
+		args = append(args, strconv.FormatFloat(member.Score, 'g', -1, 64), member.Member)
+	}
+
+	added, err := zaddScript.Run(ctx, c.rdb, []string{key}, args...).Int64()
+	if err != nil {
+		return 0, scriptError(err)
+	}
+	return added, c.invalidate(ctx, key)
+}
+
+// ZRangeByScore returns members of the sorted set within a score range.
+func (c *namespacedCache) ZRangeByScore(ctx context.Context, key string, min, max string, offset, count int64) ([]namespaced.ScoredMember, error) {
+	opt := &redis.ZRangeBy{Min: min, Max: max}
+	if count > 0 {
+		opt.Offset, opt.Count = offset, count
+	} else if offset > 0 {
+		opt.Offset, opt.Count = offset, -1
+	}
+
+	zs, err := c.rdb.ZRangeByScoreWithScores(ctx, key, opt).Result()
+	if err != nil {
+		return nil, err
+	}
+	members := make([]namespaced.ScoredMember, 0, len(zs))
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		members = append(members, namespaced.ScoredMember{Member: member, Score: z.Score})
+	}
+	return members, nil
+}
+
+// ZRank returns the rank of a member of the sorted set.
+func (c *namespacedCache) ZRank(ctx context.Context, key string, member string) (int64, bool, error) {
+	rank, err := c.rdb.ZRank(ctx, key, member).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return rank, true, nil
+}
+
+// ZIncrBy increments the score of a member of the sorted set in Redis,
+// within the quota of its namespace.
+func (c *namespacedCache) ZIncrBy(ctx context.Context, key string, member string, delta float64, ttl time.Duration) (float64, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return 0, err
+	}
+
+	policy := c.policy(key)
+	score, err := zincrbyScript.Run(ctx, c.rdb, []string{key}, policy.maxLength, policy.ttl(ttl).Milliseconds(),
+		strconv.FormatFloat(delta, 'g', -1, 64), member).Float64()
+	if err != nil {
+		return 0, scriptError(err)
+	}
+	return score, c.invalidate(ctx, key)
+}
+
+// LPush prepends values to the list in Redis, within the quota of its
+// namespace.
+func (c *namespacedCache) LPush(ctx context.Context, key string, values []*anypb.Any, ttl time.Duration) (int64, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return 0, err
+	}
+
+	policy := c.policy(key)
+	args := make([]any, 0, 2+len(values))
+	args = append(args, policy.maxLength, policy.ttl(ttl).Milliseconds())
+	for _, value := range values {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return 0, err
+		}
+		args = append(args, string(data))
+	}
+
+	length, err := lpushScript.Run(ctx, c.rdb, []string{key}, args...).Int64()
+	if err != nil {
+		return 0, scriptError(err)
+	}
+	return length, c.invalidate(ctx, key)
+}
+
+// RPop removes and returns the last value of the list in Redis.
+func (c *namespacedCache) RPop(ctx context.Context, key string) (*anypb.Any, bool, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return nil, false, err
+	}
+
+	data, err := c.rdb.RPop(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return c.popped(ctx, key, data)
+}
+
+// BRPop removes and returns the last value of the list in Redis, waiting up
+// to timeout, within the deadline of ctx, for one to arrive. It runs on the
+// pool of the blocking commands.
+func (c *namespacedCache) BRPop(ctx context.Context, key string, timeout time.Duration) (*anypb.Any, bool, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return nil, false, err
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = min(timeout, time.Until(deadline)-blockingReplyMargin)
+	}
+	// Redis blocks forever on a zero timeout and counts in milliseconds.
+	if timeout < time.Millisecond {
+		return nil, false, nil
+	}
+
+	// The command is built by hand since go-redis rounds timeouts up to
+	// whole seconds.
+	reply, err := c.blocking.Do(ctx, "brpop", key, strconv.FormatFloat(timeout.Seconds(), 'f', 3, 64)).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	// The reply is the name of the list followed by the value.
+	return c.popped(ctx, key, reply[1])
+}
+
+// popped decodes a value popped from the list and invalidates the local level.
+func (c *namespacedCache) popped(ctx context.Context, key string, data string) (*anypb.Any, bool, error) {
+	value, err := decodeValue(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, c.invalidate(ctx, key)
+}
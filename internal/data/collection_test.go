@@ -0,0 +1,107 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+)
+
+func TestNamespacedCacheCollectionPolicy(t *testing.T) {
+	data, mr := newTestData(t)
+	c := newTestNamespacedCache(t, data, &conf.Data{Namespaces: map[string]*conf.Data_Namespace{
+		"limited": {
+			DefaultTtl: durationpb.New(time.Minute),
+			MaxTtl:     durationpb.New(time.Hour),
+			MaxLength:  2,
+		},
+	}})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		key  string
+		// ttl is requested by the writes and want the TTL of the key.
+		ttl, want time.Duration
+		// limited is whether the third member or value exceeds the quota.
+		limited bool
+	}{
+		{"default ttl", "namespace:{limited}:default", 0, time.Minute, true},
+		{"requested ttl", "namespace:{limited}:requested", 10 * time.Minute, 10 * time.Minute, true},
+		{"max ttl", "namespace:{limited}:max", 2 * time.Hour, time.Hour, true},
+		{"no policy", "namespace:{free}:key", 0, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zkey, lkey := tt.key+":zset", tt.key+":list"
+			for i, member := range []string{"a", "b", "a"} {
+				if _, err := c.ZAdd(ctx, zkey, []namespaced.ScoredMember{{Member: member, Score: float64(i)}}, tt.ttl); err != nil {
+					t.Fatalf("ZAdd(%s) = %v", member, err)
+				}
+			}
+			if _, err := c.ZIncrBy(ctx, zkey, "b", 1, tt.ttl); err != nil {
+				t.Fatalf("ZIncrBy(existing) = %v", err)
+			}
+			_, err := c.ZAdd(ctx, zkey, []namespaced.ScoredMember{{Member: "c"}}, tt.ttl)
+			checkQuota(t, "ZAdd", err, tt.limited)
+			_, err = c.ZIncrBy(ctx, zkey, "d", 1, tt.ttl)
+			checkQuota(t, "ZIncrBy", err, tt.limited)
+
+			values := []*anypb.Any{mustAny(t, "1"), mustAny(t, "2")}
+			if _, err := c.LPush(ctx, lkey, values, tt.ttl); err != nil {
+				t.Fatalf("LPush = %v", err)
+			}
+			_, err = c.LPush(ctx, lkey, values[:1], tt.ttl)
+			checkQuota(t, "LPush", err, tt.limited)
+
+			for _, key := range []string{zkey, lkey} {
+				if ttl := mr.TTL(key); ttl != tt.want {
+					t.Fatalf("TTL of %s = %v, want %v", key, ttl, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func checkQuota(t *testing.T, op string, err error, limited bool) {
+	t.Helper()
+
+	if limited && errors.Reason(err) != "QUOTA_EXCEEDED" {
+		t.Fatalf("%s over the quota = %v, want QUOTA_EXCEEDED", op, err)
+	}
+	if !limited && err != nil {
+		t.Fatalf("%s without quota = %v", op, err)
+	}
+}
+
+func TestNamespacedCacheBRPop(t *testing.T) {
+	data, _ := newTestData(t)
+	c := newTestNamespacedCache(t, data, &conf.Data{})
+	key := "namespace:{test}:queue"
+
+	if _, err := c.LPush(context.Background(), key, []*anypb.Any{mustAny(t, "1")}, 0); err != nil {
+		t.Fatal(err)
+	}
+	value, found, err := c.BRPop(context.Background(), key, 100*time.Millisecond)
+	if err != nil || !found || value == nil {
+		t.Fatalf("BRPop = %v, %v, %v", value, found, err)
+	}
+
+	// An empty list is waited for up to the deadline of the request, not
+	// a whole second.
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, found, err := c.BRPop(ctx, key, time.Minute); err != nil || found {
+		t.Fatalf("BRPop on an empty list = %v, %v", found, err)
+	}
+	if elapsed := time.Since(start); elapsed >= 200*time.Millisecond {
+		t.Fatalf("BRPop returned after %v, past the deadline", elapsed)
+	}
+}
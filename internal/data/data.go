@@ -2,85 +2,229 @@ package data
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/dgraph-io/ristretto"
 	"github.com/go-kratos/kratos/v2/log"
 	"github.com/google/wire"
 	"github.com/redis/go-redis/v9"
-	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 
+	"cacheserver/internal/biz/audit"
+	"cacheserver/internal/biz/lock"
 	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/biz/ratelimit"
 	"cacheserver/internal/biz/secret"
 	"cacheserver/internal/conf"
 	"cacheserver/pkg/cache"
+	"cacheserver/pkg/cache/store"
+	boltstore "cacheserver/pkg/cache/store/bolt"
+	memorystore "cacheserver/pkg/cache/store/memory"
 	redisstore "cacheserver/pkg/cache/store/redis"
-	ristrettostore "cacheserver/pkg/cache/store/ristretto"
+	sqlstore "cacheserver/pkg/cache/store/sql"
 )
 
+// chainCloseTimeout bounds draining the background work of a chain cache on shutdown.
+const chainCloseTimeout = 5 * time.Second
+
 // ProviderSet is data providers.
 var ProviderSet = wire.NewSet(
 	NewData,
 	NewGreeterRepo,
 	NewNamespacedCache,
 	NewSecretChainCache,
+	NewAuditStore,
+	NewRateLimiter,
+	NewRedisLocker,
+	NewEventLog,
 	wire.Bind(new(namespaced.Cache), new(*namespacedCache)),
+	wire.Bind(new(namespaced.EventLog), new(*eventLog)),
 	wire.Bind(new(secret.SecretStore), new(*secretChainStore)),
+	wire.Bind(new(audit.AuditStore), new(*auditStore)),
+	wire.Bind(new(ratelimit.Limiter), new(*rateLimiter)),
+	wire.Bind(new(lock.Locker), new(*redisLocker)),
 )
 
 // Data .
 type Data struct {
 	db         *gorm.DB
-	rdb        *redis.Client
+	rdb        redis.UniversalClient
+	blocking   redis.UniversalClient // pool of the blocking commands
+	replicas   redis.UniversalClient // nil unless replica reads are enabled
+	readOpts   []redisstore.Option
+	shards     *redisstore.ShardedRedisStore // nil unless sharding is configured
 	localCache *ristretto.Cache
+	// local levels of the namespaced and secret caches
+	namespacedLocal store.Store
+	secretLocal     store.Store
+	disk            *boltstore.BoltStore // nil unless the disk level is configured
+	durable         *sqlstore.SQLStore   // nil unless durable namespaces are configured
+	diskOnly        bool                 // the disk level replaces Redis in the secret cache
 }
 
 // NewData .
 func NewData(c *conf.Data, logger log.Logger) (*Data, func(), error) {
 	helper := log.NewHelper(logger)
 
-	// Initialize MySQL
-	db, err := gorm.Open(mysql.Open(c.Database.Source), &gorm.Config{})
+	// Initialize the database selected by the configured driver
+	db, err := openDatabase(c.Database)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	// Auto migrate
-	if err := db.AutoMigrate(&SecretModel{}); err != nil {
+	if err := migrateSecrets(db); err != nil {
+		return nil, nil, err
+	}
+	if err := migrateAudit(db); err != nil {
 		return nil, nil, err
 	}
 
+	// Initialize the SQL level of the durable namespaces
+	var (
+		durable   *sqlstore.SQLStore
+		durableDB *gorm.DB
+	)
+	if len(c.GetCache().GetDurable().GetNamespaces()) > 0 {
+		durable, durableDB, err = newDurableStore(c.Cache.Durable, db, func(err error) {
+			helper.Warnf("failed to purge expired rows of the durable namespaces: %v", err)
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Initialize Redis
-	rdb := redis.NewClient(&redis.Options{
-		Addr:         c.Redis.Addr,
-		ReadTimeout:  c.Redis.ReadTimeout.AsDuration(),
-		WriteTimeout: c.Redis.WriteTimeout.AsDuration(),
-	})
+	rdb, err := newRedisClient(c.Redis)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Test Redis connection
 	if err := rdb.Ping(context.Background()).Err(); err != nil {
 		helper.Warnf("failed to connect to redis: %v", err)
 	}
 
+	// Initialize the Redis client of the blocking commands
+	blocking, err := newRedisBlockingClient(c.Redis)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialize the Redis client reading from replicas
+	replicas, err := newRedisReplicaClient(c.Redis)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Initialize the Redis shards of the secret cache
+	var shards *redisstore.ShardedRedisStore
+	if len(c.Redis.Addrs) > 0 {
+		clients := make(map[string]redis.UniversalClient, len(c.Redis.Addrs))
+		for _, addr := range c.Redis.Addrs {
+			if clients[addr], err = newRedisNode(c.Redis, addr); err != nil {
+				return nil, nil, err
+			}
+		}
+		shards = redisstore.NewSharded(clients)
+		helper.Infof("sharding the secret cache over %d redis nodes", len(clients))
+	}
+
 	// Initialize Ristretto local cache
-	localCache, err := ristretto.NewCache(&ristretto.Config{
-		NumCounters: 10000,   // number of keys to track frequency
-		MaxCost:     1 << 20, // maximum cost of cache (1MB)
-		BufferItems: 64,      // number of keys per Get buffer
-	})
+	localCache, err := newRistretto(c.GetCache().GetRistretto())
 	if err != nil {
 		return nil, nil, err
 	}
 
+	// Initialize the disk level of the secret cache
+	var disk *boltstore.BoltStore
+	if path := c.GetCache().GetDisk().GetPath(); path != "" {
+		cfg := c.Cache.Disk
+		disk, err = boltstore.NewBolt(path, boltstore.Options{
+			MaxSize:            cfg.MaxSize,
+			MaxTTL:             cfg.Ttl.AsDuration(),
+			CompactionInterval: cfg.CompactionInterval.AsDuration(),
+			OnError: func(err error) {
+				helper.Warnf("failed to purge expired entries of the disk cache: %v", err)
+			},
+		})
+		if err != nil {
+			localCache.Close()
+			return nil, nil, err
+		}
+	}
+
+	// Initialize the local levels of the chains
+	var memories []*memorystore.MemoryStore
+	closeMemories := func() {
+		for _, memory := range memories {
+			memory.Close()
+		}
+	}
+	locals := make([]store.Store, 2)
+	for i, cfg := range []*conf.Data_Cache_Local{c.GetCache().GetNamespacedLocal(), c.GetCache().GetSecretLocal()} {
+		local, memory, err := newLocalStore(cfg, localCache)
+		if err != nil {
+			closeMemories()
+			localCache.Close()
+			if disk != nil {
+				disk.Close()
+			}
+			return nil, nil, err
+		}
+		if memory != nil {
+			memories = append(memories, memory)
+		}
+		locals[i] = local
+	}
+
 	cleanup := func() {
 		helper.Info("closing the data resources")
+		closeMemories()
+		if durable != nil {
+			durable.Close()
+		}
+		if durableDB != nil {
+			if err := closeDatabase(durableDB); err != nil {
+				helper.Errorf("failed to close the database of the durable namespaces: %v", err)
+			}
+		}
 		localCache.Close()
+		if disk != nil {
+			if err := disk.Close(); err != nil {
+				helper.Errorf("failed to close the disk cache: %v", err)
+			}
+		}
 		if err := rdb.Close(); err != nil {
 			helper.Errorf("failed to close redis: %v", err)
 		}
+		if err := blocking.Close(); err != nil {
+			helper.Errorf("failed to close the redis pool of blocking commands: %v", err)
+		}
+		if replicas != nil {
+			if err := replicas.Close(); err != nil {
+				helper.Errorf("failed to close redis replicas: %v", err)
+			}
+		}
+		if shards != nil {
+			if err := shards.Close(); err != nil {
+				helper.Errorf("failed to close redis shards: %v", err)
+			}
+		}
+	}
+
+	var readOpts []redisstore.Option
+	if replicas != nil {
+		readOpts = append(readOpts, redisstore.WithReplicaReads(replicas, c.Redis.ReplicaReads.WriteMarker.AsDuration()))
+		helper.Info("reading the redis cache levels from replicas")
 	}
 
-	return &Data{db: db, rdb: rdb, localCache: localCache}, cleanup, nil
+	return &Data{db: db, rdb: rdb, blocking: blocking, replicas: replicas, readOpts: readOpts, shards: shards,
+		localCache: localCache, namespacedLocal: locals[0], secretLocal: locals[1],
+		disk: disk, durable: durable, diskOnly: disk != nil && c.Cache.Disk.ReplaceRedis}, cleanup, nil
 }
 
 // DB returns the database connection.
@@ -89,55 +233,240 @@ func (d *Data) DB() *gorm.DB {
 }
 
 // RDB returns the Redis client.
-func (d *Data) RDB() *redis.Client {
+func (d *Data) RDB() redis.UniversalClient {
 	return d.rdb
 }
 
+// BlockingRDB returns the Redis client of the commands blocking a connection
+// until a timeout, the main client if none was created.
+func (d *Data) BlockingRDB() redis.UniversalClient {
+	if d.blocking == nil {
+		return d.rdb
+	}
+	return d.blocking
+}
+
+// RedisStore returns a store on the Redis client, reading from replicas
+// when enabled.
+func (d *Data) RedisStore(opts ...redisstore.Option) *redisstore.RedisStore {
+	return redisstore.NewRedis(d.rdb, append(slices.Clone(d.readOpts), opts...)...)
+}
+
+// SecretRedisStore returns the store of the Redis level of the secret cache:
+// the configured shards, or the Redis client.
+func (d *Data) SecretRedisStore() store.Store {
+	if d.shards != nil {
+		return d.shards
+	}
+	return d.RedisStore()
+}
+
 // LocalCache returns the local Ristretto cache.
 func (d *Data) LocalCache() *ristretto.Cache {
 	return d.localCache
 }
 
+// NamespacedLocalStore returns the local level of the namespaced cache.
+func (d *Data) NamespacedLocalStore() store.Store {
+	return d.namespacedLocal
+}
+
+// SecretLocalStore returns the local level of the secret cache.
+func (d *Data) SecretLocalStore() store.Store {
+	return d.secretLocal
+}
+
+// DiskStore returns the disk level of the secret cache, or nil if it is
+// not configured.
+func (d *Data) DiskStore() *boltstore.BoltStore {
+	return d.disk
+}
+
+// DurableStore returns the SQL level of the durable namespaces, or nil if
+// none is configured.
+func (d *Data) DurableStore() *sqlstore.SQLStore {
+	return d.durable
+}
+
 // NewNamespacedCache creates a two-level cache (Local + Redis) for namespaced data.
-func NewNamespacedCache(data *Data, logger log.Logger) *namespacedCache {
+func NewNamespacedCache(c *conf.Data, data *Data, logger log.Logger) (*namespacedCache, func(), error) {
 	helper := log.NewHelper(logger)
 
-	// Level 1: Local Ristretto cache
-	localStore := ristrettostore.NewRistretto(data.LocalCache())
-	localCache := cache.New[any](localStore)
+	var durableWrite []cache.ChainOption[*namespaced.Entry]
+	if data.DurableStore() != nil {
+		// The atomic operations of the durable namespaces run on Redis, which
+		// must hold every value.
+		if policy := c.GetCache().GetDurable().GetWrite().GetPolicy(); policy == writePolicyAround {
+			return nil, nil, fmt.Errorf("write policy %q is not supported by durable namespaces", policy)
+		}
+		var err error
+		if durableWrite, err = writeOptions[*namespaced.Entry](c.GetCache().GetDurable().GetWrite()); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Level 1: Local cache, Ristretto unless configured otherwise, holding
+	// entries serialized like in Redis so that callers never share decoded
+	// messages. Hashes are cached in the same store.
+	localStore := data.NamespacedLocalStore()
+	localCache := cache.NewCodec[*namespaced.Entry](localStore, entryCodec{})
+	hashCache := cache.New[localHash](localStore)
 
 	// Level 2: Redis cache
-	redisStore := redisstore.NewRedis(data.RDB())
-	redisCache := cache.New[any](redisStore)
+	var fresh func(key string) bool
+	if namespaces := c.GetRedis().GetReplicaReads().GetFreshNamespaces(); len(namespaces) > 0 {
+		fresh = newNamespaceSet(namespaces).contains
+	}
+	redisStore := data.RedisStore(redisstore.WithFreshReads(fresh))
+	redisCache := cache.NewCodec[*namespaced.Entry](redisStore, entryCodec{})
 
 	// Create chain cache: Local -> Redis
-	chainCache := cache.NewChain[any](localCache, redisCache)
+	chainCache := cache.NewChainWithOptions([]cache.Cache[*namespaced.Entry]{localCache, redisCache},
+		cache.WithName[*namespaced.Entry]("namespaced"),
+		cache.WithBreaker[*namespaced.Entry](1, cache.DefaultBreakerConfig()),
+		cache.WithStaleIfError[*namespaced.Entry](c.GetCache().GetStaleIfError().AsDuration()),
+		cache.WithSoftTTL[*namespaced.Entry](c.GetCache().GetSoftTtl().AsDuration()),
+		cache.WithBackfill[*namespaced.Entry](backfillConfig(helper)),
+	)
 
-	helper.Info("initialized two-level cache: Local(Ristretto) -> Redis")
+	helper.Infof("initialized two-level cache: %s -> Redis", localName(localStore))
+
+	nc := &namespacedCache{
+		chain:    chainCache,
+		local:    localCache,
+		hashes:   hashCache,
+		redis:    redisStore,
+		rdb:      data.RDB(),
+		blocking: data.BlockingRDB(),
+		versions: &versionAllocator{rdb: data.RDB()},
+		policies: newNamespacePolicies(c.Namespaces),
+		log:      helper,
+		hashGens: cache.NewGenerations(0),
+	}
+	if data.DurableStore() == nil {
+		return nc, closeChain(chainCache, helper), nil
+	}
 
-	return &namespacedCache{chain: chainCache, log: helper}
+	// Level 3 of the durable namespaces: SQL key-value table. Both chains
+	// share the levels above it, and a key is always served by the same one.
+	nc.durable = cache.NewCodec[*namespaced.Entry](data.DurableStore(), entryCodec{})
+	nc.isDurable = newNamespaceSet(c.Cache.Durable.Namespaces).contains
+	nc.touches = newDurableTouches()
+	nc.durableChain = cache.NewChainWithOptions([]cache.Cache[*namespaced.Entry]{localCache, redisCache, nc.durable},
+		append([]cache.ChainOption[*namespaced.Entry]{
+			cache.WithName[*namespaced.Entry]("namespaced_durable"),
+			cache.WithBreaker[*namespaced.Entry](1, cache.DefaultBreakerConfig()),
+			cache.WithBreaker[*namespaced.Entry](2, cache.DefaultBreakerConfig()),
+			cache.WithStaleIfError[*namespaced.Entry](c.GetCache().GetStaleIfError().AsDuration()),
+			cache.WithSoftTTL[*namespaced.Entry](c.GetCache().GetSoftTtl().AsDuration()),
+			cache.WithBackfill[*namespaced.Entry](backfillConfig(helper)),
+		}, durableWrite...)...,
+	)
+
+	helper.Infof("initialized three-level cache for namespaces %v: %s -> Redis -> SQL",
+		c.Cache.Durable.Namespaces, localName(localStore))
+
+	closeChains := closeChain(chainCache, helper)
+	closeDurable := closeChain(nc.durableChain, helper)
+	return nc, func() {
+		closeChains()
+		closeDurable()
+	}, nil
 }
 
-// NewSecretChainCache creates a three-level cache (Local + Redis + MySQL) for secrets.
-func NewSecretChainCache(data *Data, logger log.Logger) *secretChainStore {
+// NewSecretChainCache creates a multi-level cache (Local + Disk + Redis + MySQL)
+// for secrets. The disk level is optional and may replace Redis.
+func NewSecretChainCache(c *conf.Data, data *Data, logger log.Logger) (*secretChainStore, func(), error) {
 	helper := log.NewHelper(logger)
 
-	// Level 1: Local Ristretto cache
-	localStore := ristrettostore.NewRistretto(data.LocalCache())
-	localCache := cache.New[any](localStore)
+	// Writes are queued in a journal with the "behind" policy, which is
+	// opened first.
+	writeOpts, err := writeOptions[*secret.SecretM](c.GetCache().GetSecretWrite())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Secrets are stored as JSON in the cache levels and decoded on every read.
+	codec := cache.JSONCodec[*secret.SecretM]{}
 
-	// Level 2: Redis cache
-	redisStore := redisstore.NewRedis(data.RDB())
-	redisCache := cache.New[any](redisStore)
+	// Level 1: Local cache, Ristretto unless configured otherwise
+	localCache := cache.NewCodec[*secret.SecretM](data.SecretLocalStore(), codec)
+
+	levels := []cache.Cache[*secret.SecretM]{localCache}
+	names := []string{localName(data.SecretLocalStore())}
 
-	// Level 3: MySQL store
+	// Optional level persisted on disk, surviving restarts
+	if disk := data.DiskStore(); disk != nil {
+		levels = append(levels, cache.NewCodec[*secret.SecretM](disk, codec))
+		names = append(names, "Disk(bbolt)")
+	}
+
+	// Redis cache, sharded if configured
+	opts := []cache.ChainOption[*secret.SecretM]{
+		cache.WithName[*secret.SecretM]("secret"),
+		cache.WithBackfill[*secret.SecretM](backfillConfig(helper)),
+	}
+	if !data.diskOnly {
+		levels = append(levels, cache.NewCodec[*secret.SecretM](data.SecretRedisStore(), codec))
+		names = append(names, "Redis")
+		// Redis is skipped while unhealthy instead of timing out on every request.
+		opts = append(opts, cache.WithBreaker[*secret.SecretM](len(levels)-1, cache.DefaultBreakerConfig()))
+	}
+
+	// Last level: MySQL store, skipped while unhealthy as well
 	mysqlStore := NewMySQLSecretStore(data.DB())
-	mysqlCache := cache.New[any](mysqlStore)
+	levels = append(levels, mysqlStore)
+	names = append(names, "MySQL")
+	opts = append(opts, cache.WithBreaker[*secret.SecretM](len(levels)-1, cache.DefaultBreakerConfig()))
+
+	chainCache := cache.NewChainWithOptions(levels, append(opts, writeOpts...)...)
+
+	helper.Infof("initialized %d-level cache: %s", len(levels), strings.Join(names, " -> "))
+
+	return &secretChainStore{chain: chainCache, db: mysqlStore, log: helper}, closeChain(chainCache, helper), nil
+}
+
+// namespaceSet is a set of namespaces, matched against namespaced cache keys.
+type namespaceSet map[string]struct{}
 
-	// Create chain cache: Local -> Redis -> MySQL
-	chainCache := cache.NewChain[any](localCache, redisCache, mysqlCache)
+// newNamespaceSet returns the set of the given namespaces.
+func newNamespaceSet(namespaces []string) namespaceSet {
+	set := make(namespaceSet, len(namespaces))
+	for _, namespace := range namespaces {
+		set[namespace] = struct{}{}
+	}
+	return set
+}
 
-	helper.Info("initialized three-level cache: Local(Ristretto) -> Redis -> MySQL")
+// contains reports whether a namespaced cache key belongs to one of the
+// namespaces of the set.
+func (s namespaceSet) contains(key string) bool {
+	parsed, ok := namespaced.ParseCacheKey(key)
+	if !ok {
+		return false
+	}
+	_, ok = s[parsed.Namespace]
+	return ok
+}
 
-	return &secretChainStore{chain: chainCache, log: helper}
+// backfillConfig returns the backfill configuration of the chain caches,
+// logging failed backfills.
+func backfillConfig(helper *log.Helper) cache.BackfillConfig {
+	return cache.BackfillConfig{
+		Policy: cache.BackfillDropOldest,
+		OnError: func(key any, level int, err error) {
+			helper.Warnf("failed to backfill key %v into cache level %d: %v", key, level, err)
+		},
+	}
+}
+
+// closeChain returns a cleanup function that drains the background work of a chain cache.
+func closeChain[T any](chain *cache.ChainCache[T], helper *log.Helper) func() {
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), chainCloseTimeout)
+		defer cancel()
+		if err := chain.Close(ctx); err != nil {
+			helper.Errorf("failed to close chain cache: %v", err)
+		}
+	}
 }
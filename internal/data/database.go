@@ -0,0 +1,52 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"cacheserver/internal/conf"
+)
+
+const (
+	// databaseDriverMySQL connects to MySQL.
+	databaseDriverMySQL = "mysql"
+	// databaseDriverPostgres connects to PostgreSQL.
+	databaseDriverPostgres = "postgres"
+	// databaseDriverSQLite opens a SQLite file, with a pure Go driver.
+	databaseDriverSQLite = "sqlite"
+)
+
+// openDatabase connects to the database selected by the configured driver,
+// MySQL by default.
+func openDatabase(c *conf.Data_Database) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch c.Driver {
+	case "", databaseDriverMySQL:
+		dialector = mysql.Open(c.Source)
+	case databaseDriverPostgres:
+		dialector = postgres.Open(c.Source)
+	case databaseDriverSQLite:
+		dialector = sqlite.Open(c.Source)
+	default:
+		return nil, fmt.Errorf("unknown database driver %q", c.Driver)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+	if c.Driver == databaseDriverSQLite {
+		// SQLite serializes writes, and every connection to ":memory:" opens
+		// a distinct database: share a single connection.
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, err
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
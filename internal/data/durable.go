@@ -0,0 +1,228 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	kerrors "github.com/go-kratos/kratos/v2/errors"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache"
+	sqlstore "cacheserver/pkg/cache/store/sql"
+)
+
+// newDurableStore creates the SQL level of the durable namespaces, in the
+// configured database or db. The returned database, if any, was opened for
+// the level and must be closed.
+func newDurableStore(c *conf.Data_Cache_Durable, db *gorm.DB, onError func(error)) (*sqlstore.SQLStore, *gorm.DB, error) {
+	var own *gorm.DB
+	if c.Database != nil {
+		var err error
+		if own, err = openDatabase(c.Database); err != nil {
+			return nil, nil, err
+		}
+		db = own
+	}
+
+	durable, err := sqlstore.NewSQL(db, sqlstore.Options{
+		Split:         splitCacheKey,
+		PurgeInterval: c.PurgeInterval.AsDuration(),
+		OnError:       onError,
+	})
+	if err != nil {
+		if own != nil {
+			closeDatabase(own)
+		}
+		return nil, nil, err
+	}
+	return durable, own, nil
+}
+
+// splitCacheKey returns the namespace and key of the row of a namespaced
+// cache key.
+func splitCacheKey(key string) (string, string) {
+	parsed, ok := namespaced.ParseCacheKey(key)
+	if !ok {
+		return "", key
+	}
+	return parsed.Namespace, parsed.Key
+}
+
+// closeDatabase closes the connections of a database.
+func closeDatabase(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// chainFor returns the chain serving a key: the one ending with the SQL
+// level for durable namespaces.
+func (c *namespacedCache) chainFor(key string) *cache.ChainCache[*namespaced.Entry] {
+	if c.durableChain != nil && c.isDurable(key) {
+		return c.durableChain
+	}
+	return c.chain
+}
+
+// errNotDurable is returned by the writes of hashes, sorted sets and lists
+// in a durable namespace, whose SQL level only holds values and counters.
+var errNotDurable = kerrors.BadRequest("NOT_DURABLE", "hashes, sorted sets and lists cannot be stored in a durable namespace")
+
+// checkNotDurable rejects the writes of a collection in a durable namespace.
+func (c *namespacedCache) checkNotDurable(key string) error {
+	if c.durableChain != nil && c.isDurable(key) {
+		return errNotDurable
+	}
+	return nil
+}
+
+// syncDurable writes a key of a durable namespace changed directly in Redis
+// through to the SQL level, following the write policy of the chain, so
+// that the level never serves a value Redis no longer holds. The value and
+// TTL are read back from Redis at once; a key gone from Redis is deleted.
+func (c *namespacedCache) syncDurable(ctx context.Context, key string) error {
+	if c.durableChain == nil || !c.isDurable(key) {
+		return nil
+	}
+
+	var (
+		get  *redis.StringCmd
+		pttl *redis.DurationCmd
+	)
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		get = pipe.Get(ctx, key)
+		pttl = pipe.PTTL(ctx, key)
+		return nil
+	})
+	if errors.Is(err, redis.Nil) {
+		return c.durableChain.DelSource(ctx, key)
+	}
+	if err != nil {
+		return err
+	}
+
+	entry, err := entryCodec{}.Unmarshal([]byte(get.Val()))
+	if err != nil {
+		return err
+	}
+	// PTTL replies with a negative value for a key without expiry.
+	return c.durableChain.SetSource(ctx, key, entry, max(pttl.Val(), 0))
+}
+
+// invalidate deletes a key changed directly in Redis from the local level,
+// discarding the backfills read before, and, for durable namespaces, writes
+// it through to the SQL level.
+func (c *namespacedCache) invalidate(ctx context.Context, key string) error {
+	c.hashGens.Advance(key)
+	if c.touches != nil {
+		c.touches.forget(key)
+	}
+	return errors.Join(c.chainFor(key).Invalidate(ctx, key, redisLevel), c.syncDurable(ctx, key))
+}
+
+// invalidateHash deletes a hash changed in Redis from the local level.
+func (c *namespacedCache) invalidateHash(ctx context.Context, key string) error {
+	c.hashGens.Advance(key)
+	return c.hashes.Del(ctx, key)
+}
+
+const (
+	// durableTouchFraction is the fraction of the TTL by which a sliding
+	// read must move the expiry of a key before it is written to the SQL level.
+	durableTouchFraction = 4
+	// minTouchSweep is the number of remembered touches below which expired
+	// ones are not swept.
+	minTouchSweep = 1024
+)
+
+// durableTouches remembers, for the keys of the durable namespaces, the
+// version and expiry last written to the SQL level by a sliding read, so
+// that the reads that barely move the expiry do not write the SQL level.
+// The SQL level may then expire a key up to a fraction of its TTL before
+// Redis does, which only matters once Redis has lost it.
+type durableTouches struct {
+	mu      sync.Mutex
+	touches map[string]durableTouch
+	sweepAt int
+}
+
+// durableTouch is the version and expiry of a key in the SQL level.
+type durableTouch struct {
+	version int64
+	expiry  time.Time
+}
+
+// newDurableTouches creates empty durable touches.
+func newDurableTouches() *durableTouches {
+	return &durableTouches{touches: make(map[string]durableTouch), sweepAt: minTouchSweep}
+}
+
+// due reports whether a sliding read resetting the TTL of a key at version
+// to ttl must be written to the SQL level, remembering it if so.
+func (t *durableTouches) due(key string, version int64, ttl time.Duration) bool {
+	now := time.Now()
+	expiry := now.Add(ttl)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if last, ok := t.touches[key]; ok && last.version == version && expiry.Sub(last.expiry) < ttl/durableTouchFraction {
+		return false
+	}
+	t.touches[key] = durableTouch{version: version, expiry: expiry}
+
+	if len(t.touches) >= t.sweepAt {
+		for k, touch := range t.touches {
+			if now.After(touch.expiry) {
+				delete(t.touches, k)
+			}
+		}
+		t.sweepAt = max(2*len(t.touches), minTouchSweep)
+	}
+	return true
+}
+
+// forget drops what is remembered of a key written by other means.
+func (t *durableTouches) forget(key string) {
+	t.mu.Lock()
+	delete(t.touches, key)
+	t.mu.Unlock()
+}
+
+// touchDurable writes the TTL of a key of a durable namespace reset by a
+// sliding read through to the SQL level, unless it barely moved.
+func (c *namespacedCache) touchDurable(ctx context.Context, key string, version int64, ttl time.Duration) error {
+	if c.durableChain == nil || !c.isDurable(key) || !c.touches.due(key, version, ttl) {
+		return nil
+	}
+	if err := c.syncDurable(ctx, key); err != nil {
+		c.touches.forget(key)
+		return err
+	}
+	return nil
+}
+
+// getExDurable reads a key of a durable namespace missing from Redis through
+// the chain and, if the SQL level holds it, restores it into Redis unless it
+// was written meanwhile, then reads it again with GETEX.
+func (c *namespacedCache) getExDurable(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	entry, _, err := c.durableChain.Lookup(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	data, err := entryCodec{}.Marshal(entry)
+	if err != nil {
+		return "", err
+	}
+	c.redis.MarkWritten(key)
+	if err := c.rdb.SetNX(ctx, key, data, ttl).Err(); err != nil {
+		return "", err
+	}
+	return c.rdb.GetEx(ctx, key, ttl).Result()
+}
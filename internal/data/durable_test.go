@@ -0,0 +1,257 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache/store"
+)
+
+// withTestDurable adds an SQLite level for the durable namespaces of c to data.
+func withTestDurable(t *testing.T, data *Data, c *conf.Data_Cache_Durable) {
+	t.Helper()
+
+	db, err := openDatabase(&conf.Data_Database{Driver: databaseDriverSQLite, Source: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	durable, _, err := newDurableStore(c, db, func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		durable.Close()
+		_ = closeDatabase(db)
+	})
+	data.durable = durable
+}
+
+func TestNamespacedCacheWriteBehind(t *testing.T) {
+	data, _ := newTestData(t)
+	durable := &conf.Data_Cache_Durable{
+		Namespaces: []string{"test"},
+		Write: &conf.Data_Cache_Write{
+			Policy:        writePolicyBehind,
+			JournalPath:   filepath.Join(t.TempDir(), "durable.journal"),
+			FlushInterval: durationpb.New(10 * time.Millisecond),
+		},
+	}
+	withTestDurable(t, data, durable)
+	c := newTestNamespacedCache(t, data, &conf.Data{Cache: &conf.Data_Cache{Durable: durable}})
+	ctx := context.Background()
+	key := "namespace:{test}:key"
+
+	if err := c.Set(ctx, key, mustAny(t, "v")); err != nil {
+		t.Fatal(err)
+	}
+	// Redis is written immediately, the SQL level once the journal is flushed.
+	if _, err := data.RDB().Get(ctx, key).Result(); err != nil {
+		t.Fatalf("Redis: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		_, err := c.durable.Get(ctx, key)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, store.ErrKeyNotFound) || time.Now().After(deadline) {
+			t.Fatalf("SQL level: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNamespacedCacheDurableWriteThrough(t *testing.T) {
+	data, mr := newTestData(t)
+	durable := &conf.Data_Cache_Durable{Namespaces: []string{"test"}}
+	withTestDurable(t, data, durable)
+	c := newTestNamespacedCache(t, data, &conf.Data{Cache: &conf.Data_Cache{Durable: durable}})
+	ctx := context.Background()
+	key, counter := "namespace:{test}:key", "namespace:{test}:counter"
+
+	var version int64
+	steps := []struct {
+		name string
+		key  string
+		op   func() error
+	}{
+		{"compare and set", key, func() (err error) {
+			version, _, err = c.CompareAndSet(ctx, key, mustAny(t, "v"), 0, time.Minute)
+			return err
+		}},
+		{"incr", counter, func() error {
+			_, err := c.IncrBy(ctx, counter, 5, 0)
+			return err
+		}},
+		{"incr with cap", counter, func() error {
+			_, _, err := c.IncrWithCap(ctx, counter, 3, 10, 0)
+			return err
+		}},
+		{"touch", counter, func() error {
+			_, err := c.Touch(ctx, counter, time.Hour)
+			return err
+		}},
+		{"get and touch", key, func() error {
+			_, err := c.GetAndTouch(ctx, key, 2*time.Hour)
+			return err
+		}},
+		{"compare and delete", key, func() error {
+			_, err := c.CompareAndDelete(ctx, key, version)
+			return err
+		}},
+	}
+	for _, step := range steps {
+		if err := step.op(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		// The SQL level holds what Redis holds, with the same TTL.
+		value, ttl, err := data.DurableStore().GetWithTTL(ctx, step.key)
+		if !mr.Exists(step.key) {
+			if !errors.Is(err, store.ErrKeyNotFound) {
+				t.Fatalf("%s: SQL level = %v, want deleted", step.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: SQL level: %v", step.name, err)
+		}
+		want, _ := mr.Get(step.key)
+		if got := string(value.([]byte)); got != want {
+			t.Fatalf("%s: SQL level holds %s, want %s", step.name, got, want)
+		}
+		if wantTTL := mr.TTL(step.key); ttl > wantTTL || ttl < wantTTL-time.Second {
+			t.Fatalf("%s: SQL level TTL = %v, want %v", step.name, ttl, wantTTL)
+		}
+	}
+
+	// Counters survive a flush of Redis and keep counting.
+	mr.FlushAll()
+	if _, err := c.Get(ctx, counter); err != nil {
+		t.Fatal(err)
+	}
+	// Wait for the backfill of Redis.
+	deadline := time.Now().Add(5 * time.Second)
+	for !mr.Exists(counter) {
+		if time.Now().After(deadline) {
+			t.Fatal("counter never restored in Redis")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got, err := c.IncrBy(ctx, counter, 1, 0); err != nil || got != 9 {
+		t.Fatalf("IncrBy after flush = %d, %v, want 9", got, err)
+	}
+}
+
+func TestNamespacedCacheDurableGetAndTouch(t *testing.T) {
+	data, mr := newTestData(t)
+	durable := &conf.Data_Cache_Durable{Namespaces: []string{"test"}}
+	withTestDurable(t, data, durable)
+	c := newTestNamespacedCache(t, data, &conf.Data{Cache: &conf.Data_Cache{Durable: durable}})
+	ctx := context.Background()
+	key := "namespace:{test}:key"
+
+	if _, err := c.GetAndTouch(ctx, key, time.Hour); !errors.Is(err, store.ErrKeyNotFound) {
+		t.Fatalf("GetAndTouch of a missing key = %v, want %v", err, store.ErrKeyNotFound)
+	}
+
+	// A key only held by the SQL level is found and restored into Redis.
+	if err := c.SetWithTTL(ctx, key, mustAny(t, "v"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	mr.Del(key)
+	if _, err := c.GetAndTouch(ctx, key, 2*time.Hour); err != nil {
+		t.Fatalf("GetAndTouch of a key missing from Redis: %v", err)
+	}
+	if ttl := mr.TTL(key); ttl != 2*time.Hour {
+		t.Fatalf("Redis TTL = %v, want %v", ttl, 2*time.Hour)
+	}
+
+	sqlTTL := func() time.Duration {
+		t.Helper()
+		_, ttl, err := data.DurableStore().GetWithTTL(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ttl
+	}
+	if ttl := sqlTTL(); ttl <= time.Hour {
+		t.Fatalf("SQL level TTL = %v, want the sliding TTL", ttl)
+	}
+
+	// Reads barely moving the expiry do not write the SQL level.
+	value, err := mr.Get(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := data.DurableStore().SetWithTTL(ctx, key, []byte(value), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.GetAndTouch(ctx, key, 2*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := sqlTTL(); ttl > time.Minute {
+		t.Fatalf("SQL level TTL = %v, want the touch skipped", ttl)
+	}
+	if _, err := c.GetAndTouch(ctx, key, 4*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if ttl := sqlTTL(); ttl <= 3*time.Hour {
+		t.Fatalf("SQL level TTL = %v, want the sliding TTL", ttl)
+	}
+}
+
+func TestNamespacedCacheDurableCollections(t *testing.T) {
+	data, _ := newTestData(t)
+	durable := &conf.Data_Cache_Durable{Namespaces: []string{"test"}}
+	withTestDurable(t, data, durable)
+	c := newTestNamespacedCache(t, data, &conf.Data{Cache: &conf.Data_Cache{Durable: durable}})
+	ctx := context.Background()
+
+	ops := []struct {
+		name string
+		op   func(key string) error
+	}{
+		{"hset", func(key string) error {
+			_, err := c.HSet(ctx, key, map[string]*anypb.Any{"f": mustAny(t, "v")}, 0)
+			return err
+		}},
+		{"hdel", func(key string) error {
+			_, err := c.HDel(ctx, key, []string{"f"})
+			return err
+		}},
+		{"zadd", func(key string) error {
+			_, err := c.ZAdd(ctx, key, []namespaced.ScoredMember{{Member: "m", Score: 1}}, 0)
+			return err
+		}},
+		{"zincrby", func(key string) error {
+			_, err := c.ZIncrBy(ctx, key, "m", 1, 0)
+			return err
+		}},
+		{"lpush", func(key string) error {
+			_, err := c.LPush(ctx, key, []*anypb.Any{mustAny(t, "v")}, 0)
+			return err
+		}},
+		{"rpop", func(key string) error {
+			_, _, err := c.RPop(ctx, key)
+			return err
+		}},
+	}
+	for _, tt := range ops {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.op("namespace:{test}:" + tt.name); !errors.Is(err, errNotDurable) {
+				t.Fatalf("durable namespace: %v, want %v", err, errNotDurable)
+			}
+			if err := tt.op("namespace:{other}:" + tt.name); err != nil {
+				t.Fatalf("other namespace: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"cacheserver/pkg/cache"
+)
+
+// errWrongType is returned when a key is read with an operation that does
+// not match the type of value stored at it.
+var errWrongType = errors.BadRequest("WRONG_TYPE", "operation against a key holding the wrong kind of value")
+
+// isWrongType reports whether err is the Redis error replied to an
+// operation against a key holding the wrong kind of value.
+func isWrongType(err error) bool {
+	var redisErr redis.Error
+	return errors.As(err, &redisErr) && strings.HasPrefix(redisErr.Error(), "WRONGTYPE")
+}
+
+// localHash is the local copy of a Redis hash, holding the serialized
+// field values so that callers never share decoded messages.
+type localHash map[string]string
+
+// HSet sets fields of the hash in Redis, applying ttl to the key when
+// positive, and invalidates the local copy.
+func (c *namespacedCache) HSet(ctx context.Context, key string, fields map[string]*anypb.Any, ttl time.Duration) (int64, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return 0, err
+	}
+
+	values := make(map[string]any, len(fields))
+	for field, value := range fields {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return 0, err
+		}
+		values[field] = string(data)
+	}
+
+	var added *redis.IntCmd
+	_, err := c.rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		added = pipe.HSet(ctx, key, values)
+		if ttl > 0 {
+			pipe.PExpire(ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return added.Val(), c.invalidateHash(ctx, key)
+}
+
+// HGet returns a field of the hash, served from the local copy when present.
+func (c *namespacedCache) HGet(ctx context.Context, key string, field string) (*anypb.Any, bool, error) {
+	hash, ok, err := c.localHash(ctx, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var data string
+	if ok {
+		if data, ok = hash[field]; !ok {
+			return nil, false, nil
+		}
+	} else {
+		data, err = c.rdb.HGet(ctx, key, field).Result()
+		if errors.Is(err, redis.Nil) {
+			return nil, false, nil
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	value, err := decodeValue(data)
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// HDel deletes fields of the hash in Redis and invalidates the local copy.
+func (c *namespacedCache) HDel(ctx context.Context, key string, fields []string) (int64, error) {
+	if err := c.checkNotDurable(key); err != nil {
+		return 0, err
+	}
+
+	deleted, err := c.rdb.HDel(ctx, key, fields...).Result()
+	if err != nil {
+		return 0, err
+	}
+	return deleted, c.invalidateHash(ctx, key)
+}
+
+// HGetAll returns every field of the hash. A miss loads the whole hash from
+// Redis and caches it locally with the TTL of the key, unless the key was
+// written meanwhile.
+func (c *namespacedCache) HGetAll(ctx context.Context, key string) (map[string]*anypb.Any, error) {
+	hash, ok, err := c.localHash(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		gen, read := c.hashGens.Current(), time.Now()
+		var all *redis.MapStringStringCmd
+		var pttl *redis.DurationCmd
+		_, err := c.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			all = pipe.HGetAll(ctx, key)
+			pttl = pipe.PTTL(ctx, key)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		hash = localHash(all.Val())
+		if len(hash) > 0 {
+			c.cacheHash(ctx, key, hash, pttl.Val(), gen, read)
+		}
+	}
+
+	fields := make(map[string]*anypb.Any, len(hash))
+	for field, data := range hash {
+		value, err := decodeValue(data)
+		if err != nil {
+			return nil, err
+		}
+		fields[field] = value
+	}
+	return fields, nil
+}
+
+// localHash returns the local copy of the hash at key and whether it exists.
+func (c *namespacedCache) localHash(ctx context.Context, key string) (localHash, bool, error) {
+	hash, err := c.hashes.Get(ctx, key)
+	if errors.Is(err, cache.ErrTypeMismatch) {
+		return nil, false, errWrongType
+	}
+	if err != nil {
+		// The local level only fails on a miss.
+		return nil, false, nil
+	}
+	return hash, true, nil
+}
+
+// cacheHash stores the local copy of a hash read at generation gen, unless
+// the key was written since. A negative ttl means the key has no expiry.
+func (c *namespacedCache) cacheHash(ctx context.Context, key string, hash localHash, ttl time.Duration, gen uint64, read time.Time) {
+	if c.hashGens.Outdated(key, gen, read) {
+		return
+	}
+
+	var err error
+	if ttl > 0 {
+		err = c.hashes.SetWithTTL(ctx, key, hash, ttl)
+	} else {
+		err = c.hashes.Set(ctx, key, hash)
+	}
+	if err != nil {
+		c.log.Warnf("failed to cache hash %q locally: %v", key, err)
+		return
+	}
+	if c.hashGens.Outdated(key, gen, read) {
+		// A write raced with this one; do not leave the hash behind.
+		_ = c.hashes.Del(ctx, key)
+	}
+}
+
+// decodeValue deserializes a hash field or list value.
+func decodeValue(data string) (*anypb.Any, error) {
+	value := &anypb.Any{}
+	if err := json.Unmarshal([]byte(data), value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
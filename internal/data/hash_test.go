@@ -0,0 +1,49 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"cacheserver/internal/conf"
+)
+
+func TestNamespacedCacheHGetAll(t *testing.T) {
+	data, _ := newTestData(t)
+	c := newTestNamespacedCache(t, data, &conf.Data{})
+	ctx := context.Background()
+	key := "namespace:{test}:hash"
+
+	if _, err := c.HSet(ctx, key, map[string]*anypb.Any{"a": mustAny(t, "1")}, 0); err != nil {
+		t.Fatal(err)
+	}
+	// A snapshot read before a write is not cached after it.
+	gen, read := c.hashGens.Current(), time.Now()
+	stale, err := c.rdb.HGetAll(ctx, key).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.HSet(ctx, key, map[string]*anypb.Any{"a": mustAny(t, "2")}, 0); err != nil {
+		t.Fatal(err)
+	}
+	c.cacheHash(ctx, key, localHash(stale), -1, gen, read)
+	if _, ok, _ := c.localHash(ctx, key); ok {
+		t.Fatal("hash read before HSet cached after it")
+	}
+
+	for range 2 {
+		fields, err := c.HGetAll(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !proto.Equal(fields["a"], mustAny(t, "2")) {
+			t.Fatalf("HGetAll returned %v, want the value of the last HSet", fields["a"])
+		}
+		if _, ok, _ := c.localHash(ctx, key); !ok {
+			t.Fatal("hash not cached locally after HGetAll")
+		}
+	}
+}
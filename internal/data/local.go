@@ -0,0 +1,113 @@
+package data
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/ristretto"
+
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache/store"
+	memorystore "cacheserver/pkg/cache/store/memory"
+	ristrettostore "cacheserver/pkg/cache/store/ristretto"
+)
+
+const (
+	// localStoreRistretto is the Ristretto cache shared by the chains.
+	localStoreRistretto = "ristretto"
+	// localStoreMemory is a dedicated in-memory store.
+	localStoreMemory = "memory"
+	// localPolicyLRU evicts the least recently used entry of a memory store.
+	localPolicyLRU = "lru"
+	// localPolicyLFU evicts the least frequently used entry of a memory store.
+	localPolicyLFU = "lfu"
+
+	// defaultRistrettoNumCounters is the default number of keys whose access
+	// frequency is tracked.
+	defaultRistrettoNumCounters = 1_000_000
+	// defaultRistrettoMaxCost is the default size of the values, in bytes.
+	defaultRistrettoMaxCost = 64 << 20
+	// defaultRistrettoBufferItems is the default number of keys per Get buffer.
+	defaultRistrettoBufferItems = 64
+)
+
+// newRistretto creates the Ristretto cache shared by the local levels and
+// reports its metrics. Costs are sizes in bytes.
+func newRistretto(c *conf.Data_Cache_Ristretto) (*ristretto.Cache, error) {
+	cfg := &ristretto.Config{
+		NumCounters:        c.GetNumCounters(),
+		MaxCost:            c.GetMaxCost(),
+		BufferItems:        c.GetBufferItems(),
+		Metrics:            true,
+		IgnoreInternalCost: true,
+	}
+	if cfg.NumCounters <= 0 {
+		cfg.NumCounters = defaultRistrettoNumCounters
+	}
+	if cfg.MaxCost <= 0 {
+		cfg.MaxCost = defaultRistrettoMaxCost
+	}
+	if cfg.BufferItems <= 0 {
+		cfg.BufferItems = defaultRistrettoBufferItems
+	}
+
+	cache, err := ristretto.NewCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if err := ristrettostore.ObserveMetrics("local", cache.Metrics); err != nil {
+		cache.Close()
+		return nil, err
+	}
+	return cache, nil
+}
+
+// newLocalStore creates the local level of a chain selected by the
+// configuration. The returned memory store, if any, must be closed.
+func newLocalStore(c *conf.Data_Cache_Local, shared *ristretto.Cache) (store.Store, *memorystore.MemoryStore, error) {
+	switch c.GetStore() {
+	case "", localStoreRistretto:
+		return ristrettostore.NewRistretto(shared, ristrettostore.WithCoster(localSize)), nil, nil
+	case localStoreMemory:
+		var policy memorystore.Policy
+		switch c.Policy {
+		case "", localPolicyLRU:
+			policy = memorystore.LRU
+		case localPolicyLFU:
+			policy = memorystore.LFU
+		default:
+			return nil, nil, fmt.Errorf("unknown local cache policy %q", c.Policy)
+		}
+		memory := memorystore.NewMemory(memorystore.Options{
+			Policy:  policy,
+			MaxSize: c.MaxSize,
+			Shards:  int(c.Shards),
+			Sizer:   localSize,
+		})
+		return memory, memory, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown local cache store %q", c.GetStore())
+	}
+}
+
+// localName describes a local level in logs.
+func localName(local store.Store) string {
+	if _, ok := local.(*memorystore.MemoryStore); ok {
+		return "Local(Memory)"
+	}
+	return "Local(Ristretto)"
+}
+
+// localSize returns the size of a value of a local level, including the
+// hashes cached by the namespaced cache. It is the cost of the value in
+// Ristretto.
+func localSize(value any) int64 {
+	hash, ok := value.(localHash)
+	if !ok {
+		return memorystore.DefaultSizer(value)
+	}
+	var size int64
+	for field, data := range hash {
+		size += int64(len(field) + len(data))
+	}
+	return size
+}
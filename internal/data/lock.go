@@ -0,0 +1,89 @@
+package data
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// acquireLockScript sets KEYS[1] to the owner token ARGV[1] with a TTL of
+// ARGV[2] milliseconds if the lock is free, and increments the fencing
+// counter KEYS[2]. If the lock is already held by the same token the lease
+// is extended and the current fencing token returned, a new one if the
+// counter was lost. It returns 0 when the lock is held by someone else.
+//
+// A missing counter is seeded with the Redis time in microseconds, so that
+// the tokens handed out after it was lost are above the ones handed out
+// before, as long as fewer than one lock per microsecond was acquired.
+var acquireLockScript = redis.NewScript(`
+local function fence()
+  if redis.call('EXISTS', KEYS[2]) == 0 then
+    local now = redis.call('TIME')
+    redis.call('SET', KEYS[2], now[1] .. string.format('%06d', tonumber(now[2])))
+  end
+  return redis.call('INCR', KEYS[2])
+end
+local owner = redis.call('GET', KEYS[1])
+if not owner then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+  return fence()
+end
+if owner == ARGV[1] then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+  local current = redis.call('GET', KEYS[2])
+  if not current then return fence() end
+  return tonumber(current)
+end
+return 0
+`)
+
+// renewLockScript extends the TTL of KEYS[1] to ARGV[2] milliseconds if it is held by ARGV[1].
+var renewLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseLockScript deletes KEYS[1] if it is held by ARGV[1].
+var releaseLockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// redisLocker implements the lock.Locker interface on Redis.
+type redisLocker struct {
+	rdb redis.UniversalClient
+}
+
+// NewRedisLocker creates a Redis backed distributed locker.
+func NewRedisLocker(data *Data) *redisLocker {
+	return &redisLocker{rdb: data.RDB()}
+}
+
+// fenceKey returns the key of the fencing counter for a lock.
+func fenceKey(key string) string {
+	return key + ":fence"
+}
+
+// Acquire takes the lock for token if it is free or already held by token.
+func (l *redisLocker) Acquire(ctx context.Context, key string, token string, ttl time.Duration) (int64, bool, error) {
+	fence, err := acquireLockScript.Run(ctx, l.rdb, []string{key, fenceKey(key)}, token, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return 0, false, err
+	}
+	return fence, fence > 0, nil
+}
+
+// Renew extends the lease if the lock is held by token.
+func (l *redisLocker) Renew(ctx context.Context, key string, token string, ttl time.Duration) (bool, error) {
+	return renewLockScript.Run(ctx, l.rdb, []string{key}, token, ttl.Milliseconds()).Bool()
+}
+
+// Release frees the lock if it is held by token.
+func (l *redisLocker) Release(ctx context.Context, key string, token string) (bool, error) {
+	return releaseLockScript.Run(ctx, l.rdb, []string{key}, token).Bool()
+}
@@ -0,0 +1,43 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRedisLockerAcquire(t *testing.T) {
+	data, mr := newTestData(t)
+	locker := NewRedisLocker(data)
+	ctx := context.Background()
+	key := "lock:{test}:job"
+
+	first, ok, err := locker.Acquire(ctx, key, "a", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("Acquire(a) = %v, %v", ok, err)
+	}
+	if _, ok, err := locker.Acquire(ctx, key, "b", time.Minute); err != nil || ok {
+		t.Fatalf("Acquire(b) on a held lock = %v, %v", ok, err)
+	}
+	again, ok, err := locker.Acquire(ctx, key, "a", time.Minute)
+	if err != nil || !ok || again != first {
+		t.Fatalf("re-Acquire(a) = %d, %v, %v, want %d, true", again, ok, err, first)
+	}
+
+	// The owner keeps the lock after the counter was evicted, with a new
+	// fencing token above the ones handed out before.
+	mr.Del(fenceKey(key))
+	mr.SetTime(time.Now().Add(time.Second))
+	fence, ok, err := locker.Acquire(ctx, key, "a", time.Minute)
+	if err != nil || !ok || fence <= first {
+		t.Fatalf("re-Acquire(a) without counter = %d, %v, %v, want a token above %d", fence, ok, err, first)
+	}
+
+	if released, err := locker.Release(ctx, key, "a"); err != nil || !released {
+		t.Fatalf("Release(a) = %v, %v", released, err)
+	}
+	next, ok, err := locker.Acquire(ctx, key, "b", time.Minute)
+	if err != nil || !ok || next <= fence {
+		t.Fatalf("Acquire(b) = %d, %v, %v, want a token above %d", next, ok, err, fence)
+	}
+}
@@ -0,0 +1,179 @@
+package data
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"cacheserver/internal/biz/ratelimit"
+	memorystore "cacheserver/pkg/cache/store/memory"
+)
+
+// gcraScript implements the generic cell rate algorithm. KEYS[1] holds the
+// theoretical arrival time (TAT) in microseconds. ARGV[1] is the emission
+// interval, ARGV[2] the burst tolerance (the window) and ARGV[3] the cost,
+// durations in microseconds. It returns {allowed, remaining,
+// retry_after_us, reset_after_us}.
+var gcraScript = redis.NewScript(`
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000000 + tonumber(time[2])
+local interval = tonumber(ARGV[1])
+local tolerance = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+
+local tat = tonumber(redis.call('GET', KEYS[1]))
+if not tat or tat < now then tat = now end
+
+local new_tat = tat + interval * cost
+local diff = now - (new_tat - tolerance)
+if diff < 0 then
+  local remaining = math.floor((now - (tat - tolerance)) / interval)
+  if remaining < 0 then remaining = 0 end
+  return {0, remaining, tostring(-diff), tostring(tat - now)}
+end
+
+local ttl = math.max(1, math.ceil((new_tat - now) / 1000))
+redis.call('SET', KEYS[1], string.format('%.3f', new_tat), 'PX', ttl)
+return {1, math.floor(diff / interval), '0', tostring(new_tat - now)}
+`)
+
+// localBucketsMaxSize bounds the size of the local token buckets, in bytes.
+const localBucketsMaxSize = 8 << 20
+
+// tokenBucket is a per-instance token bucket mirroring a rate limit. It only
+// counts requests the shared limiter allowed, so an empty bucket means this
+// instance alone has used up the limit and the shared limiter would deny.
+type tokenBucket struct {
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+	limit  ratelimit.Limit
+}
+
+// refill adds the tokens accrued since the last call. Callers hold b.mu.
+func (b *tokenBucket) refill(now time.Time) {
+	rate := float64(b.limit.Limit) / float64(b.limit.Window)
+	b.tokens += float64(now.Sub(b.last)) * rate
+	if b.tokens > float64(b.limit.Limit) {
+		b.tokens = float64(b.limit.Limit)
+	}
+	b.last = now
+}
+
+// wait returns how long until cost tokens are available, 0 if they are now.
+func (b *tokenBucket) wait(cost int64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	missing := float64(cost) - b.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(missing * float64(b.limit.Window) / float64(b.limit.Limit))
+}
+
+// take removes cost tokens from the bucket.
+func (b *tokenBucket) take(cost int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.tokens -= float64(cost)
+}
+
+// rateLimiter implements the ratelimit.Limiter interface with GCRA in Redis
+// and an optional local token bucket pre-check.
+type rateLimiter struct {
+	rdb redis.UniversalClient
+	// buckets holds the local token buckets, apart from the cache levels so
+	// that they never evict cached values.
+	buckets *memorystore.MemoryStore
+}
+
+// NewRateLimiter creates a Redis backed rate limiter.
+func NewRateLimiter(data *Data) (*rateLimiter, func()) {
+	buckets := memorystore.NewMemory(memorystore.Options{MaxSize: localBucketsMaxSize})
+	return &rateLimiter{rdb: data.RDB(), buckets: buckets}, buckets.Close
+}
+
+// Allow consumes cost units for key if the limit permits it.
+func (l *rateLimiter) Allow(ctx context.Context, key string, limit ratelimit.Limit, cost int64, localPrecheck bool) (*ratelimit.Result, error) {
+	var bucket *tokenBucket
+	if localPrecheck {
+		bucket = l.bucket(ctx, key, limit)
+		if wait := bucket.wait(cost); wait > 0 {
+			return &ratelimit.Result{Allowed: false, RetryAfter: wait, ResetAfter: limit.Window}, nil
+		}
+	}
+
+	interval := float64(limit.Window.Microseconds()) / float64(limit.Limit)
+	reply, err := gcraScript.Run(ctx, l.rdb, []string{key},
+		strconv.FormatFloat(interval, 'f', -1, 64), limit.Window.Microseconds(), cost).Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := parseGCRAReply(reply)
+	if err != nil {
+		return nil, err
+	}
+	if result.Allowed && bucket != nil {
+		bucket.take(cost)
+	}
+	return result, nil
+}
+
+// bucket returns the local token bucket for key, creating it if needed.
+func (l *rateLimiter) bucket(ctx context.Context, key string, limit ratelimit.Limit) *tokenBucket {
+	if value, err := l.buckets.Get(ctx, key); err == nil {
+		if bucket, ok := value.(*tokenBucket); ok && bucket.limit == limit {
+			return bucket
+		}
+	}
+
+	bucket := &tokenBucket{tokens: float64(limit.Limit), last: time.Now(), limit: limit}
+	// A failed set only means the pre-check starts from a full bucket next time.
+	_ = l.buckets.SetWithTTL(ctx, key, bucket, limit.Window)
+	return bucket
+}
+
+// parseGCRAReply converts the reply of gcraScript into a result.
+func parseGCRAReply(reply []any) (*ratelimit.Result, error) {
+	if len(reply) != 4 {
+		return nil, fmt.Errorf("unexpected reply from rate limit script: %v", reply)
+	}
+	allowed, _ := reply[0].(int64)
+	remaining, _ := reply[1].(int64)
+	retryAfter, err := parseMicros(reply[2])
+	if err != nil {
+		return nil, err
+	}
+	resetAfter, err := parseMicros(reply[3])
+	if err != nil {
+		return nil, err
+	}
+	return &ratelimit.Result{
+		Allowed:    allowed == 1,
+		Remaining:  remaining,
+		RetryAfter: retryAfter,
+		ResetAfter: resetAfter,
+	}, nil
+}
+
+// parseMicros parses a fractional microsecond string into a duration.
+func parseMicros(value any) (time.Duration, error) {
+	str, ok := value.(string)
+	if !ok {
+		return 0, fmt.Errorf("unexpected duration in rate limit reply: %v", value)
+	}
+	us, err := strconv.ParseFloat(str, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(us * float64(time.Microsecond)), nil
+}
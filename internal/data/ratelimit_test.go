@@ -0,0 +1,64 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"cacheserver/internal/biz/ratelimit"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	data, _ := newTestData(t)
+	limiter, cleanup := NewRateLimiter(data)
+	t.Cleanup(cleanup)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		limit ratelimit.Limit
+		// cost is consumed by each request of the burst: only the first
+		// allowed ones fit in the limit, whatever the time between them.
+		cost    int64
+		allowed int
+	}{
+		{"seconds", ratelimit.Limit{Limit: 3, Window: time.Minute}, 1, 3},
+		{"single millisecond", ratelimit.Limit{Limit: 1, Window: time.Millisecond}, 2, 0},
+		{"limit above window in ms", ratelimit.Limit{Limit: 50, Window: 10 * time.Millisecond}, 51, 0},
+		{"whole limit in one request", ratelimit.Limit{Limit: 50, Window: 10 * time.Millisecond}, 50, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, precheck := range []bool{false, true} {
+				key := "ratelimit:test:" + tt.name
+				if precheck {
+					key += ":precheck"
+				}
+				for i := range tt.allowed {
+					result, err := limiter.Allow(ctx, key, tt.limit, tt.cost, precheck)
+					if err != nil {
+						t.Fatal(err)
+					}
+					if !result.Allowed {
+						t.Fatalf("request %d denied, want allowed", i)
+					}
+				}
+				if tt.allowed > 0 && tt.limit.Window < time.Second {
+					// The next request may come after the window.
+					continue
+				}
+
+				result, err := limiter.Allow(ctx, key, tt.limit, tt.cost, precheck)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result.Allowed {
+					t.Fatal("request over the limit allowed")
+				}
+				if result.RetryAfter <= 0 {
+					t.Fatalf("retry after %v, want positive", result.RetryAfter)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package data
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+
+	"github.com/redis/go-redis/v9"
+
+	"cacheserver/internal/conf"
+)
+
+const (
+	// redisModeStandalone connects to a single Redis server.
+	redisModeStandalone = "standalone"
+	// redisModeCluster connects to a Redis Cluster.
+	redisModeCluster = "cluster"
+	// redisModeSentinel connects to the primary of a Sentinel-managed set.
+	redisModeSentinel = "sentinel"
+
+	// replicaRouteRandom reads from a random replica.
+	replicaRouteRandom = "random"
+	// replicaRouteLatency reads from the node with the lowest latency.
+	replicaRouteLatency = "latency"
+
+	// defaultBlockingPoolSize is the default size of the pool of blocking commands.
+	defaultBlockingPoolSize = 10
+)
+
+// newRedisClient creates the Redis client selected by the configured mode.
+func newRedisClient(c *conf.Data_Redis) (redis.UniversalClient, error) {
+	opts, err := redisOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	return newRedisModeClient(c, opts)
+}
+
+// newRedisBlockingClient creates a client of the same Redis as
+// newRedisClient with its own pool, for the commands holding a connection
+// until a timeout. Their reads are bounded by the request deadline rather
+// than the read timeout.
+func newRedisBlockingClient(c *conf.Data_Redis) (redis.UniversalClient, error) {
+	opts, err := redisOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	opts.PoolSize = int(c.BlockingPoolSize)
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = defaultBlockingPoolSize
+	}
+	opts.MinIdleConns = 0
+	opts.ReadTimeout = -1
+	opts.ContextTimeoutEnabled = true
+	return newRedisModeClient(c, opts)
+}
+
+// newRedisModeClient creates a client with opts for the configured mode.
+func newRedisModeClient(c *conf.Data_Redis, opts *redis.UniversalOptions) (redis.UniversalClient, error) {
+	switch c.Mode {
+	case "", redisModeStandalone:
+		opts.Addrs = []string{c.Addr}
+		return redis.NewClient(opts.Simple()), nil
+	case redisModeCluster:
+		opts.Addrs = c.ClusterAddrs
+		if len(opts.Addrs) == 0 {
+			opts.Addrs = []string{c.Addr}
+		}
+		return redis.NewClusterClient(opts.Cluster()), nil
+	case redisModeSentinel:
+		if c.MasterName == "" || len(c.SentinelAddrs) == 0 {
+			return nil, fmt.Errorf("redis sentinel mode requires master_name and sentinel_addrs")
+		}
+		opts.Addrs = c.SentinelAddrs
+		opts.MasterName = c.MasterName
+		return redis.NewFailoverClient(opts.Failover()), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", c.Mode)
+	}
+}
+
+// newRedisReplicaClient creates a client routing reads to the replicas of
+// the configured Redis, or returns nil when replica reads are disabled.
+func newRedisReplicaClient(c *conf.Data_Redis) (redis.UniversalClient, error) {
+	replicas := c.GetReplicaReads()
+	if !replicas.GetEnabled() {
+		return nil, nil
+	}
+	opts, err := redisOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	switch replicas.Route {
+	case "", replicaRouteRandom:
+		opts.ReadOnly = true
+	case replicaRouteLatency:
+		opts.RouteByLatency = true
+	default:
+		return nil, fmt.Errorf("unknown redis replica route %q", replicas.Route)
+	}
+
+	switch c.Mode {
+	case "", redisModeStandalone:
+		if len(replicas.Addrs) == 0 {
+			return nil, fmt.Errorf("redis replica reads in standalone mode require replica_reads.addrs")
+		}
+		// A single slot range served by the primary and its replicas lets the
+		// cluster client route reads without Redis Cluster.
+		opts.Addrs = append([]string{c.Addr}, replicas.Addrs...)
+		nodes := make([]redis.ClusterNode, 0, len(opts.Addrs))
+		for _, addr := range opts.Addrs {
+			nodes = append(nodes, redis.ClusterNode{Addr: addr})
+		}
+		clusterOpts := opts.Cluster()
+		clusterOpts.ClusterSlots = func(context.Context) ([]redis.ClusterSlot, error) {
+			return []redis.ClusterSlot{{Start: 0, End: 16383, Nodes: nodes}}, nil
+		}
+		return redis.NewClusterClient(clusterOpts), nil
+	case redisModeCluster:
+		opts.Addrs = c.ClusterAddrs
+		if len(opts.Addrs) == 0 {
+			opts.Addrs = []string{c.Addr}
+		}
+		return redis.NewClusterClient(opts.Cluster()), nil
+	case redisModeSentinel:
+		opts.Addrs = c.SentinelAddrs
+		opts.MasterName = c.MasterName
+		failoverOpts := opts.Failover()
+		failoverOpts.ReplicaOnly = opts.ReadOnly
+		failoverOpts.RouteByLatency = opts.RouteByLatency
+		return redis.NewFailoverClusterClient(failoverOpts), nil
+	default:
+		return nil, fmt.Errorf("unknown redis mode %q", c.Mode)
+	}
+}
+
+// newRedisNode creates a client of the standalone Redis server at addr,
+// sharing the credentials, TLS and pool settings of the configuration.
+func newRedisNode(c *conf.Data_Redis, addr string) (redis.UniversalClient, error) {
+	opts, err := redisOptions(c)
+	if err != nil {
+		return nil, err
+	}
+	opts.Addrs = []string{addr}
+	return redis.NewClient(opts.Simple()), nil
+}
+
+// redisOptions returns the client options shared by every mode.
+func redisOptions(c *conf.Data_Redis) (*redis.UniversalOptions, error) {
+	tlsConfig, err := redisTLSConfig(c.Tls)
+	if err != nil {
+		return nil, err
+	}
+	return &redis.UniversalOptions{
+		Username:         c.Username,
+		Password:         c.Password,
+		SentinelUsername: c.SentinelUsername,
+		SentinelPassword: c.SentinelPassword,
+		DB:               int(c.Db),
+		TLSConfig:        tlsConfig,
+		PoolSize:         int(c.PoolSize),
+		MinIdleConns:     int(c.MinIdleConns),
+		PoolTimeout:      c.PoolTimeout.AsDuration(),
+		DialTimeout:      c.DialTimeout.AsDuration(),
+		ReadTimeout:      c.ReadTimeout.AsDuration(),
+		WriteTimeout:     c.WriteTimeout.AsDuration(),
+	}, nil
+}
+
+// redisTLSConfig returns the TLS configuration of the Redis connections, or
+// nil when TLS is disabled.
+func redisTLSConfig(c *conf.Data_Redis_TLS) (*tls.Config, error) {
+	if !c.GetEnabled() {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+	if c.CaFile != "" {
+		pem, err := os.ReadFile(c.CaFile)
+		if err != nil {
+			return nil, fmt.Errorf("read redis ca file: %w", err)
+		}
+		config.RootCAs = x509.NewCertPool()
+		if !config.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in redis ca file %q", c.CaFile)
+		}
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load redis client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
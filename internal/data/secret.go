@@ -2,24 +2,32 @@ package data
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"time"
 
 	"github.com/go-kratos/kratos/v2/log"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"cacheserver/internal/biz/secret"
 	"cacheserver/pkg/cache"
 	"cacheserver/pkg/cache/store"
 )
 
+// Ensure that *mysqlSecretStore serves as the last level of the secret chain.
+var _ cache.Cache[*secret.SecretM] = (*mysqlSecretStore)(nil)
+
 // SecretModel represents the database model for secrets.
+//
+// Rows are soft-deleted. DeletedID is 0 for live rows and is set to the row ID
+// on deletion, so the unique index on (secret_id, deleted_id) only constrains
+// live rows and a deleted secret ID can be reused.
 type SecretModel struct {
 	gorm.Model
 	UserID      string `gorm:"column:user_id;type:varchar(64)"`
 	Name        string `gorm:"column:name;type:varchar(253)"`
-	SecretID    string `gorm:"column:secret_id;type:varchar(64);uniqueIndex"`
+	SecretID    string `gorm:"column:secret_id;type:varchar(64);uniqueIndex:idx_secrets_secret_id_deleted_id"`
+	DeletedID   uint   `gorm:"column:deleted_id;not null;default:0;uniqueIndex:idx_secrets_secret_id_deleted_id"`
 	SecretKey   string `gorm:"column:secret_key;type:varchar(255)"`
 	Expires     int64  `gorm:"column:expires"`
 	Status      int32  `gorm:"column:status;default:1"`
@@ -31,36 +39,63 @@ func (SecretModel) TableName() string {
 	return "secrets"
 }
 
+// legacySecretIDIndex is the unique index on secret_id alone created by
+// earlier versions, which prevents reusing the ID of a soft-deleted secret.
+const legacySecretIDIndex = "idx_secrets_secret_id"
+
+// migrateSecrets migrates the secrets table, dropping the legacy unique index.
+// Rows soft-deleted before deleted_id existed are moved out of the live slot
+// first, so that their secret IDs can be reused and undeleted.
+func migrateSecrets(db *gorm.DB) error {
+	if err := db.AutoMigrate(&SecretModel{}); err != nil {
+		return err
+	}
+	if err := db.Unscoped().Model(&SecretModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_id = 0").
+		UpdateColumn("deleted_id", gorm.Expr("id")).Error; err != nil {
+		return err
+	}
+	if db.Migrator().HasIndex(&SecretModel{}, legacySecretIDIndex) {
+		return db.Migrator().DropIndex(&SecretModel{}, legacySecretIDIndex)
+	}
+	return nil
+}
+
+// toSecretM converts a database model into a secret model.
+func toSecretM(model *SecretModel) *secret.SecretM {
+	secretM := &secret.SecretM{
+		ID:          int64(model.ID),
+		UserID:      model.UserID,
+		Name:        model.Name,
+		SecretID:    model.SecretID,
+		SecretKey:   model.SecretKey,
+		Expires:     model.Expires,
+		Status:      model.Status,
+		Description: model.Description,
+		CreatedAt:   model.CreatedAt,
+		UpdatedAt:   model.UpdatedAt,
+	}
+	if model.DeletedAt.Valid {
+		secretM.DeletedAt = model.DeletedAt.Time
+	}
+	return secretM
+}
+
 // secretChainStore implements the secret.SecretStore interface using chain cache.
 type secretChainStore struct {
-	chain *cache.ChainCache[any]
+	chain *cache.ChainCache[*secret.SecretM]
+	db    *mysqlSecretStore
 	log   *log.Helper
 }
 
 // Set stores or updates a secret in the chain cache.
 func (s *secretChainStore) Set(ctx context.Context, key string, value *secret.SecretM) error {
-	// Serialize to JSON for storage in cache layers
-	data, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-	return s.chain.Set(ctx, key, string(data))
+	return s.chain.Set(ctx, key, value)
 }
 
 // Get retrieves a secret from the chain cache.
 func (s *secretChainStore) Get(ctx context.Context, key string) (*secret.SecretM, error) {
-	result, err := s.chain.Get(ctx, key)
-	if err != nil {
-		return nil, err
-	}
-
-	var secretM secret.SecretM
-	if str, ok := result.(string); ok {
-		if err := json.Unmarshal([]byte(str), &secretM); err != nil {
-			return nil, err
-		}
-	}
-	return &secretM, nil
+	return s.chain.Get(ctx, key)
 }
 
 // Del removes a secret from the chain cache.
@@ -68,7 +103,27 @@ func (s *secretChainStore) Del(ctx context.Context, key string) error {
 	return s.chain.Del(ctx, key)
 }
 
-// mysqlSecretStore implements store.Store interface for MySQL.
+// List lists secrets from MySQL, bypassing the cache levels.
+func (s *secretChainStore) List(ctx context.Context, opts *secret.ListOptions) (int64, []*secret.SecretM, error) {
+	return s.db.List(ctx, opts)
+}
+
+// Undelete restores a soft-deleted secret in MySQL. The cache levels never
+// hold deleted secrets, so they are repopulated on the next read.
+func (s *secretChainStore) Undelete(ctx context.Context, key string) error {
+	return s.db.Undelete(ctx, key)
+}
+
+// Purge removes a secret from every cache level and permanently deletes it
+// from MySQL.
+func (s *secretChainStore) Purge(ctx context.Context, key string) error {
+	if err := s.chain.Del(ctx, key); err != nil {
+		return err
+	}
+	return s.db.Purge(ctx, key)
+}
+
+// mysqlSecretStore implements cache.Cache for secrets stored in MySQL.
 type mysqlSecretStore struct {
 	db *gorm.DB
 }
@@ -79,7 +134,7 @@ func NewMySQLSecretStore(db *gorm.DB) *mysqlSecretStore {
 }
 
 // Get retrieves a secret from MySQL.
-func (s *mysqlSecretStore) Get(ctx context.Context, key any) (any, error) {
+func (s *mysqlSecretStore) Get(ctx context.Context, key any) (*secret.SecretM, error) {
 	var model SecretModel
 	if err := s.db.WithContext(ctx).Where(SecretModel{SecretID: key.(string)}).First(&model).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -87,30 +142,11 @@ func (s *mysqlSecretStore) Get(ctx context.Context, key any) (any, error) {
 		}
 		return nil, err
 	}
-
-	secretM := &secret.SecretM{
-		ID:          int64(model.ID),
-		UserID:      model.UserID,
-		Name:        model.Name,
-		SecretID:    model.SecretID,
-		SecretKey:   model.SecretKey,
-		Expires:     model.Expires,
-		Status:      model.Status,
-		Description: model.Description,
-		CreatedAt:   model.CreatedAt,
-		UpdatedAt:   model.UpdatedAt,
-	}
-
-	// Return as JSON string for consistency with other cache layers
-	data, err := json.Marshal(secretM)
-	if err != nil {
-		return nil, err
-	}
-	return string(data), nil
+	return toSecretM(&model), nil
 }
 
 // GetWithTTL retrieves a secret and its TTL from MySQL.
-func (s *mysqlSecretStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+func (s *mysqlSecretStore) GetWithTTL(ctx context.Context, key any) (*secret.SecretM, time.Duration, error) {
 	value, err := s.Get(ctx, key)
 	if err != nil {
 		return nil, 0, err
@@ -120,14 +156,7 @@ func (s *mysqlSecretStore) GetWithTTL(ctx context.Context, key any) (any, time.D
 }
 
 // Set stores a secret in MySQL.
-func (s *mysqlSecretStore) Set(ctx context.Context, key any, value any) error {
-	var secretM secret.SecretM
-	if str, ok := value.(string); ok {
-		if err := json.Unmarshal([]byte(str), &secretM); err != nil {
-			return err
-		}
-	}
-
+func (s *mysqlSecretStore) Set(ctx context.Context, key any, secretM *secret.SecretM) error {
 	model := &SecretModel{
 		UserID:      secretM.UserID,
 		Name:        secretM.Name,
@@ -138,28 +167,101 @@ func (s *mysqlSecretStore) Set(ctx context.Context, key any, value any) error {
 		Description: secretM.Description,
 	}
 
-	return s.db.WithContext(ctx).Where(SecretModel{SecretID: key.(string)}).
-		Assign(model).
-		FirstOrCreate(model).Error
+	model.SecretID = key.(string)
+
+	// A single upsert on the unique (secret_id, deleted_id) index, which only
+	// conflicts with the live row since deleted rows hold their own ID. A row
+	// soft-deleted without deleted_id, as by replicas predating it, is made
+	// live again rather than updated while staying invisible.
+	updates := append(clause.AssignmentColumns([]string{
+		"user_id", "name", "secret_key", "expires", "status", "description", "updated_at",
+	}), clause.Assignment{Column: clause.Column{Name: "deleted_at"}, Value: nil})
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "secret_id"}, {Name: "deleted_id"}},
+		DoUpdates: updates,
+	}).Create(model).Error
 }
 
 // SetWithTTL stores a secret in MySQL (TTL is ignored for MySQL).
-func (s *mysqlSecretStore) SetWithTTL(ctx context.Context, key any, value any, ttl time.Duration) error {
+func (s *mysqlSecretStore) SetWithTTL(ctx context.Context, key any, value *secret.SecretM, ttl time.Duration) error {
 	return s.Set(ctx, key, value)
 }
 
-// Del removes a secret from MySQL.
+// Del soft-deletes a secret in MySQL.
 func (s *mysqlSecretStore) Del(ctx context.Context, key any) error {
-	err := s.db.WithContext(ctx).Where(SecretModel{SecretID: key.(string)}).Delete(&SecretModel{}).Error
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return err
-	}
-	return nil
+	return s.softDelete(s.db.WithContext(ctx).Where(SecretModel{SecretID: key.(string)}))
 }
 
-// Clear clears all secrets from MySQL.
+// Clear soft-deletes all secrets in MySQL.
 func (s *mysqlSecretStore) Clear(ctx context.Context) error {
-	return s.db.WithContext(ctx).Where("1 = 1").Delete(&SecretModel{}).Error
+	return s.softDelete(s.db.WithContext(ctx).Where("1 = 1"))
+}
+
+// softDelete marks the live secrets matched by query as deleted.
+func (s *mysqlSecretStore) softDelete(query *gorm.DB) error {
+	return query.Model(&SecretModel{}).Updates(map[string]any{
+		"deleted_at": time.Now(),
+		"deleted_id": gorm.Expr("id"),
+	}).Error
+}
+
+// List lists secrets from MySQL, newest first.
+func (s *mysqlSecretStore) List(ctx context.Context, opts *secret.ListOptions) (int64, []*secret.SecretM, error) {
+	query := s.db.WithContext(ctx).Model(&SecretModel{})
+	if opts.ShowDeleted {
+		query = query.Unscoped()
+	}
+	if opts.UserID != "" {
+		query = query.Where("user_id = ?", opts.UserID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return 0, nil, err
+	}
+
+	var models []*SecretModel
+	if err := query.Order("id DESC").Offset(opts.Offset).Limit(opts.Limit).Find(&models).Error; err != nil {
+		return 0, nil, err
+	}
+
+	secrets := make([]*secret.SecretM, 0, len(models))
+	for _, model := range models {
+		secrets = append(secrets, toSecretM(model))
+	}
+	return total, secrets, nil
+}
+
+// Undelete restores the most recently soft-deleted secret with the given key.
+func (s *mysqlSecretStore) Undelete(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var live int64
+		if err := tx.Model(&SecretModel{}).Where(SecretModel{SecretID: key}).Count(&live).Error; err != nil {
+			return err
+		}
+		if live > 0 {
+			return secret.ErrSecretExists
+		}
+
+		var model SecretModel
+		err := tx.Unscoped().Where("secret_id = ? AND deleted_id <> 0", key).Order("deleted_at DESC").First(&model).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return store.ErrKeyNotFound
+			}
+			return err
+		}
+
+		return tx.Unscoped().Model(&model).Updates(map[string]any{
+			"deleted_at": nil,
+			"deleted_id": 0,
+		}).Error
+	})
+}
+
+// Purge permanently deletes every row, live or soft-deleted, with the given key.
+func (s *mysqlSecretStore) Purge(ctx context.Context, key string) error {
+	return s.db.WithContext(ctx).Unscoped().Where(SecretModel{SecretID: key}).Delete(&SecretModel{}).Error
 }
 
 // Wait waits for all operations to complete.
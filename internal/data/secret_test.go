@@ -0,0 +1,175 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"cacheserver/internal/biz/secret"
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache/store"
+)
+
+// newTestSecretStore returns the secret chain over Redis, local stores and
+// a migrated in-memory SQLite database.
+func newTestSecretStore(t *testing.T) (*secretChainStore, *gorm.DB) {
+	t.Helper()
+
+	db, err := openDatabase(&conf.Data_Database{Driver: databaseDriverSQLite, Source: ":memory:"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.Logger = logger.Default.LogMode(logger.Silent)
+	t.Cleanup(func() { _ = closeDatabase(db) })
+	if err := migrateSecrets(db); err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := newTestData(t)
+	data.db = db
+	s, cleanup, err := NewSecretChainCache(&conf.Data{}, data, log.DefaultLogger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(cleanup)
+	return s, db
+}
+
+// countSecrets returns the number of live secrets of a user and of rows,
+// deleted included.
+func countSecrets(t *testing.T, s *secretChainStore, userID string) (live, all int64) {
+	t.Helper()
+
+	ctx := context.Background()
+	live, _, err := s.List(ctx, &secret.ListOptions{UserID: userID, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	all, _, err = s.List(ctx, &secret.ListOptions{UserID: userID, ShowDeleted: true, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return live, all
+}
+
+func TestSecretStore(t *testing.T) {
+	s, _ := newTestSecretStore(t)
+	ctx := context.Background()
+	set := func(name string) error {
+		return s.Set(ctx, "s1", &secret.SecretM{UserID: "u1", SecretID: "s1", Name: name})
+	}
+
+	steps := []struct {
+		name string
+		op   func() error
+		err  error
+		// want is the name of the live secret, empty if there is none.
+		want      string
+		live, all int64
+	}{
+		{"create", func() error { return set("a") }, nil, "a", 1, 1},
+		{"update in place", func() error {
+			created, err := s.db.Get(ctx, "s1")
+			if err != nil {
+				return err
+			}
+			if err := set("b"); err != nil {
+				return err
+			}
+			updated, err := s.db.Get(ctx, "s1")
+			if err != nil {
+				return err
+			}
+			if updated.ID != created.ID {
+				t.Fatalf("update moved the secret from row %d to %d", created.ID, updated.ID)
+			}
+			return nil
+		}, nil, "b", 1, 1},
+		{"delete", func() error { return s.Del(ctx, "s1") }, nil, "", 0, 1},
+		{"reuse the id", func() error { return set("c") }, nil, "c", 1, 2},
+		{"undelete over a live secret", func() error { return s.Undelete(ctx, "s1") }, secret.ErrSecretExists, "c", 1, 2},
+		{"delete again", func() error { return s.Del(ctx, "s1") }, nil, "", 0, 2},
+		{"undelete the latest", func() error { return s.Undelete(ctx, "s1") }, nil, "c", 1, 2},
+		{"purge", func() error { return s.Purge(ctx, "s1") }, nil, "", 0, 0},
+	}
+	for _, step := range steps {
+		if err := step.op(); !errors.Is(err, step.err) {
+			t.Fatalf("%s: %v, want %v", step.name, err, step.err)
+		}
+
+		got, err := s.Get(ctx, "s1")
+		if step.want == "" {
+			if !errors.Is(err, store.ErrKeyNotFound) {
+				t.Fatalf("%s: Get = %v, want not found", step.name, err)
+			}
+		} else if err != nil {
+			t.Fatalf("%s: Get: %v", step.name, err)
+		} else if got.Name != step.want {
+			t.Fatalf("%s: name = %q, want %q", step.name, got.Name, step.want)
+		}
+
+		if live, all := countSecrets(t, s, "u1"); live != step.live || all != step.all {
+			t.Fatalf("%s: %d live of %d rows, want %d of %d", step.name, live, all, step.live, step.all)
+		}
+	}
+}
+
+func TestSecretStoreLegacyDeletedRow(t *testing.T) {
+	s, db := newTestSecretStore(t)
+	ctx := context.Background()
+
+	// Rows soft-deleted before deleted_id existed, as by an older replica,
+	// still hold the live slot of their secret ID.
+	legacy := func(secretID string) *SecretModel {
+		t.Helper()
+		model := &SecretModel{UserID: "u1", SecretID: secretID, Name: "old"}
+		model.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+		if err := db.Create(model).Error; err != nil {
+			t.Fatal(err)
+		}
+		return model
+	}
+
+	// The upsert makes such a row live again instead of updating it while
+	// it stays deleted.
+	legacy("upserted")
+	if err := s.Set(ctx, "upserted", &secret.SecretM{UserID: "u1", Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.db.Get(ctx, "upserted")
+	if err != nil {
+		t.Fatalf("upserted legacy row: %v", err)
+	}
+	if got.Name != "new" {
+		t.Fatalf("name = %q, want %q", got.Name, "new")
+	}
+
+	// The migration moves them out of the live slot, so that the secret ID
+	// can be reused.
+	migrated := legacy("migrated")
+	if err := migrateSecrets(db); err != nil {
+		t.Fatal(err)
+	}
+	var model SecretModel
+	if err := db.Unscoped().First(&model, migrated.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if model.DeletedID != model.ID {
+		t.Fatalf("deleted_id = %d, want %d", model.DeletedID, model.ID)
+	}
+	if err := s.Set(ctx, "migrated", &secret.SecretM{UserID: "u1", Name: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	var rows int64
+	if err := db.Unscoped().Model(&SecretModel{}).Where("secret_id = ?", "migrated").Count(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Fatalf("%d rows of the reused secret ID, want 2", rows)
+	}
+}
@@ -0,0 +1,510 @@
+package data
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/errors"
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+
+	v1 "cacheserver/api/cacheserver/v1"
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+)
+
+const (
+	// watchRetention is how long events are kept for resuming watchers.
+	watchRetention = 10 * time.Minute
+	// watchBlockTimeout bounds each blocking read of a namespace stream.
+	watchBlockTimeout = time.Second
+	// watchBatchSize is the maximum number of events read at once.
+	watchBatchSize = 100
+	// watchSubscriberBuffer is the number of events buffered per watcher.
+	watchSubscriberBuffer = 256
+	// watchRetryInterval is the delay before retrying a failed stream read.
+	watchRetryInterval = time.Second
+	// expiredDedupTTL is how long the instance recording the expirations of
+	// a key keeps that role, so that only one server instance records them.
+	expiredDedupTTL = time.Second
+	// expiredChannel is the keyspace notification channel for expired keys.
+	expiredChannel = "__keyevent@*__:expired"
+	// watchQueueSize bounds the number of events waiting to be appended.
+	watchQueueSize = 10000
+	// watchAppendBatch is the maximum number of events appended in one pipeline.
+	watchAppendBatch = 100
+)
+
+var (
+	// errResumeTokenExpired is returned when events after a resume token may
+	// already have been trimmed.
+	errResumeTokenExpired = errors.BadRequest("RESUME_TOKEN_EXPIRED", "resume token is outside the retention window")
+	// errInvalidResumeToken is returned for malformed resume tokens.
+	errInvalidResumeToken = errors.BadRequest("INVALID_RESUME_TOKEN", "invalid resume token")
+	// errWatcherTooSlow is returned to a watcher that could not keep up with events.
+	errWatcherTooSlow = errors.ServiceUnavailable("WATCHER_TOO_SLOW", "watcher fell behind, resume with the last token")
+)
+
+// claimExpiredScript claims the expiration of a key for the instance ARGV[1],
+// holding KEYS[1] for ARGV[2] milliseconds, and returns 1 if it was claimed.
+//
+// Every instance receives each notification exactly once and in order, so
+// the holder of the claim has already seen the previous expirations: the
+// notification it sees again is a new expiration of the key, which it claims
+// too, rather than a duplicate of the one it recorded.
+var claimExpiredScript = redis.NewScript(`
+local holder = redis.call('GET', KEYS[1])
+if holder and holder ~= ARGV[1] then return 0 end
+redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// watchStreamKey returns the Redis stream holding the events of a namespace.
+func watchStreamKey(namespace string) string {
+	return "watch:" + namespace
+}
+
+// parseStreamID splits a Redis stream ID into its millisecond and sequence parts.
+func parseStreamID(id string) (int64, int64, bool) {
+	msPart, seqPart, ok := strings.Cut(id, "-")
+	if !ok {
+		return 0, 0, false
+	}
+	ms, err := strconv.ParseInt(msPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	seq, err := strconv.ParseInt(seqPart, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return ms, seq, true
+}
+
+// streamIDAfter reports whether stream ID a sorts after b. Both must be valid.
+func streamIDAfter(a, b string) bool {
+	aMs, aSeq, _ := parseStreamID(a)
+	bMs, bSeq, _ := parseStreamID(b)
+	return aMs > bMs || (aMs == bMs && aSeq > bSeq)
+}
+
+// toEvent converts a stream message into an event.
+func toEvent(msg redis.XMessage) *namespaced.Event {
+	event := &namespaced.Event{ResumeToken: msg.ID}
+	if ms, _, ok := parseStreamID(msg.ID); ok {
+		event.Time = time.UnixMilli(ms)
+	}
+	if key, ok := msg.Values["key"].(string); ok {
+		event.Key = key
+	}
+	if typ, ok := msg.Values["type"].(string); ok {
+		n, _ := strconv.ParseInt(typ, 10, 32)
+		event.Type = v1.WatchEvent_Type(n)
+	}
+	return event
+}
+
+// watchSub is a single watcher registered with a hub.
+type watchSub struct {
+	events chan redis.XMessage
+	err    error
+}
+
+// watchHub tails the stream of one namespace and fans events out to every
+// watcher of that namespace, so that watchers share one blocking read.
+type watchHub struct {
+	subs   map[*watchSub]struct{}
+	cancel context.CancelFunc
+}
+
+// watchAppend is an event waiting to be appended to a namespace stream.
+type watchAppend struct {
+	namespace string
+	event     *namespaced.Event
+}
+
+// eventLog implements the namespaced.EventLog interface on Redis streams,
+// one stream per namespace trimmed to the retention window. Events are
+// appended in the background, so that writes do not wait for them.
+// Expirations are picked up from Redis keyspace notifications.
+type eventLog struct {
+	rdb       redis.UniversalClient
+	log       *log.Helper
+	configure bool
+	// instance identifies this server instance in expiration claims.
+	instance string
+
+	mu   sync.Mutex
+	hubs map[string]*watchHub
+
+	// queueMu guards closed: the queue is only closed once no Append can
+	// send on it.
+	queueMu sync.RWMutex
+	queue   chan watchAppend
+	closed  bool
+	written chan struct{}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEventLog creates an event log and starts listening for key expirations.
+func NewEventLog(c *conf.Data, data *Data, logger log.Logger) (*eventLog, func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	l := &eventLog{
+		rdb:       data.RDB(),
+		log:       log.NewHelper(logger),
+		configure: c.GetWatch().GetConfigureNotifications(),
+		instance:  uuid.New().String(),
+		hubs:      make(map[string]*watchHub),
+		queue:     make(chan watchAppend, watchQueueSize),
+		written:   make(chan struct{}),
+		cancel:    cancel,
+	}
+
+	go l.writeEvents()
+	l.wg.Add(1)
+	go l.listenExpired(ctx)
+
+	cleanup := func() {
+		l.cancel()
+		l.wg.Wait()
+
+		l.queueMu.Lock()
+		l.closed = true
+		close(l.queue)
+		l.queueMu.Unlock()
+		<-l.written
+	}
+	return l, cleanup
+}
+
+// Append queues an event for the namespace stream. Events are appended in
+// order, pipelined with the other pending ones; when the queue is full the
+// event is dropped rather than blocking the write path.
+func (l *eventLog) Append(_ context.Context, namespace string, event *namespaced.Event) {
+	l.queueMu.RLock()
+	defer l.queueMu.RUnlock()
+	if l.closed {
+		return
+	}
+	select {
+	case l.queue <- watchAppend{namespace: namespace, event: event}:
+	default:
+		l.log.Warnf("watch queue is full, dropping %s event for key %q in namespace %q", event.Type, event.Key, namespace)
+	}
+}
+
+// writeEvents appends the queued events until the queue is closed.
+func (l *eventLog) writeEvents() {
+	defer close(l.written)
+
+	batch := make([]watchAppend, 0, watchAppendBatch)
+	for first := range l.queue {
+		batch = append(batch[:0], first)
+	drain:
+		for len(batch) < watchAppendBatch {
+			select {
+			case next, ok := <-l.queue:
+				if !ok {
+					break drain
+				}
+				batch = append(batch, next)
+			default:
+				break drain
+			}
+		}
+		l.appendBatch(batch)
+	}
+}
+
+// appendBatch appends events to their namespace streams in one pipeline.
+func (l *eventLog) appendBatch(batch []watchAppend) {
+	ctx := context.Background()
+	minID := strconv.FormatInt(time.Now().Add(-watchRetention).UnixMilli(), 10)
+
+	_, err := l.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, a := range batch {
+			key := watchStreamKey(a.namespace)
+			pipe.XAdd(ctx, &redis.XAddArgs{
+				Stream: key,
+				MinID:  minID,
+				Approx: true,
+				Values: map[string]any{"type": int32(a.event.Type), "key": a.event.Key},
+			})
+			// Idle streams disappear once every event has left the retention window.
+			pipe.PExpire(ctx, key, watchRetention)
+		}
+		return nil
+	})
+	if err != nil {
+		l.log.Errorf("failed to record %d watch events: %v", len(batch), err)
+	}
+}
+
+// Watch replays events after resumeToken and then streams new events to fn.
+func (l *eventLog) Watch(ctx context.Context, namespace string, resumeToken string, fn func(*namespaced.Event) error) error {
+	if resumeToken != "" {
+		ms, _, ok := parseStreamID(resumeToken)
+		if !ok {
+			return errInvalidResumeToken
+		}
+		if time.UnixMilli(ms).Before(time.Now().Add(-watchRetention)) {
+			return errResumeTokenExpired
+		}
+	}
+
+	// Subscribe before replaying so that no event falls between the two.
+	sub, err := l.subscribe(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	defer l.unsubscribe(namespace, sub)
+
+	last := resumeToken
+	if resumeToken != "" {
+		if last, err = l.replay(ctx, namespace, resumeToken, fn); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-sub.events:
+			if !ok {
+				return sub.err
+			}
+			if last != "" && !streamIDAfter(msg.ID, last) {
+				continue
+			}
+			if err := fn(toEvent(msg)); err != nil {
+				return err
+			}
+			last = msg.ID
+		}
+	}
+}
+
+// replay calls fn for every stored event after the given ID and returns the
+// ID of the last event replayed.
+func (l *eventLog) replay(ctx context.Context, namespace string, after string, fn func(*namespaced.Event) error) (string, error) {
+	key := watchStreamKey(namespace)
+	for {
+		msgs, err := l.rdb.XRangeN(ctx, key, "("+after, "+", watchBatchSize).Result()
+		if err != nil {
+			return after, err
+		}
+		for _, msg := range msgs {
+			if err := fn(toEvent(msg)); err != nil {
+				return after, err
+			}
+			after = msg.ID
+		}
+		if len(msgs) < watchBatchSize {
+			return after, nil
+		}
+	}
+}
+
+// subscribe registers a watcher with the hub of a namespace, starting the hub if needed.
+func (l *eventLog) subscribe(ctx context.Context, namespace string) (*watchSub, error) {
+	sub := &watchSub{events: make(chan redis.XMessage, watchSubscriberBuffer)}
+	if l.join(namespace, sub) {
+		return sub, nil
+	}
+
+	// Resolve the current end of the stream now, so the hub only misses
+	// events that a resuming watcher will replay. Redis is not called under
+	// l.mu, which every other watcher needs.
+	start := "0-0"
+	msgs, err := l.rdb.XRevRangeN(ctx, watchStreamKey(namespace), "+", "-", 1).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(msgs) > 0 {
+		start = msgs[0].ID
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if hub, ok := l.hubs[namespace]; ok {
+		// Started by another watcher in the meantime.
+		hub.subs[sub] = struct{}{}
+		return sub, nil
+	}
+	hubCtx, cancel := context.WithCancel(context.Background())
+	hub := &watchHub{subs: map[*watchSub]struct{}{sub: {}}, cancel: cancel}
+	l.hubs[namespace] = hub
+	go l.runHub(hubCtx, namespace, hub, start)
+	return sub, nil
+}
+
+// join registers a watcher with the running hub of a namespace, if any.
+func (l *eventLog) join(namespace string, sub *watchSub) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hub, ok := l.hubs[namespace]
+	if ok {
+		hub.subs[sub] = struct{}{}
+	}
+	return ok
+}
+
+// unsubscribe removes a watcher, stopping the hub when it was the last one.
+func (l *eventLog) unsubscribe(namespace string, sub *watchSub) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	hub, ok := l.hubs[namespace]
+	if !ok {
+		return
+	}
+	delete(hub.subs, sub)
+	if len(hub.subs) == 0 {
+		hub.cancel()
+		delete(l.hubs, namespace)
+	}
+}
+
+// runHub tails the namespace stream and dispatches events until ctx is done.
+func (l *eventLog) runHub(ctx context.Context, namespace string, hub *watchHub, last string) {
+	key := watchStreamKey(namespace)
+	for ctx.Err() == nil {
+		streams, err := l.rdb.XRead(ctx, &redis.XReadArgs{
+			Streams: []string{key, last},
+			Count:   watchBatchSize,
+			Block:   watchBlockTimeout,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() == nil {
+				l.log.Errorf("failed to read events of namespace %q: %v", namespace, err)
+				time.Sleep(watchRetryInterval)
+			}
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				l.dispatch(hub, msg)
+				last = msg.ID
+			}
+		}
+	}
+}
+
+// dispatch delivers an event to every watcher of a hub, dropping watchers
+// whose buffer is full.
+func (l *eventLog) dispatch(hub *watchHub, msg redis.XMessage) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for sub := range hub.subs {
+		select {
+		case sub.events <- msg:
+		default:
+			sub.err = errWatcherTooSlow
+			close(sub.events)
+			delete(hub.subs, sub)
+		}
+	}
+}
+
+// listenExpired records an EXPIRE event for every expired namespaced key.
+// Keyspace notifications are local to each node of a cluster, so every
+// primary known at startup is listened to.
+func (l *eventLog) listenExpired(ctx context.Context) {
+	defer l.wg.Done()
+
+	cluster, ok := l.rdb.(*redis.ClusterClient)
+	if !ok {
+		l.listenNode(ctx, l.rdb)
+		return
+	}
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+		l.listenNode(ctx, client)
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		l.log.Warnf("failed to listen to expirations on every cluster node, expire events may be missing: %v", err)
+	}
+}
+
+// listenNode records the expirations notified by a Redis node until ctx is done.
+func (l *eventLog) listenNode(ctx context.Context, client redis.UniversalClient) {
+	l.enableExpiredNotifications(ctx, client)
+
+	pubsub := client.PSubscribe(ctx, expiredChannel)
+	defer pubsub.Close()
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			l.handleExpired(ctx, msg.Payload)
+		}
+	}
+}
+
+// handleExpired records the expiration of a namespaced key. Every server
+// instance receives the notification, so only the one holding the claim on
+// the expirations of the key records the event.
+func (l *eventLog) handleExpired(ctx context.Context, cacheKey string) {
+	key, ok := namespaced.ParseCacheKey(cacheKey)
+	if !ok {
+		return
+	}
+
+	claimed, err := claimExpiredScript.Run(ctx, l.rdb, []string{"watch:expired:" + cacheKey},
+		l.instance, expiredDedupTTL.Milliseconds()).Bool()
+	if err != nil {
+		l.log.Errorf("failed to claim expiration of key %q: %v", cacheKey, err)
+		return
+	}
+	if claimed {
+		l.Append(ctx, key.Namespace, &namespaced.Event{Type: v1.WatchEvent_EXPIRE, Key: key.Key, Time: time.Now()})
+	}
+}
+
+// enableExpiredNotifications checks that a Redis node publishes expired
+// keyevents, and enables them if configured to. CONFIG SET changes the
+// configuration of the whole server and is often denied by managed Redis.
+func (l *eventLog) enableExpiredNotifications(ctx context.Context, client redis.UniversalClient) {
+	config, err := client.ConfigGet(ctx, "notify-keyspace-events").Result()
+	if err != nil {
+		l.log.Warnf("failed to read notify-keyspace-events, expire events may be missing: %v", err)
+		return
+	}
+
+	flags := config["notify-keyspace-events"]
+	if strings.Contains(flags, "E") && (strings.Contains(flags, "x") || strings.Contains(flags, "A")) {
+		return
+	}
+	if !l.configure {
+		l.log.Warnf("expired keyevent notifications are disabled (notify-keyspace-events %q), expire events will be missing", flags)
+		return
+	}
+	if !strings.Contains(flags, "E") {
+		flags += "E"
+	}
+	if !strings.Contains(flags, "x") {
+		flags += "x"
+	}
+	if err := client.ConfigSet(ctx, "notify-keyspace-events", flags).Err(); err != nil {
+		l.log.Warnf("failed to enable expired keyspace notifications, expire events may be missing: %v", err)
+	}
+}
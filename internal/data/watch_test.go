@@ -0,0 +1,109 @@
+package data
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-kratos/kratos/v2/log"
+
+	v1 "cacheserver/api/cacheserver/v1"
+	"cacheserver/internal/biz/namespaced"
+	"cacheserver/internal/conf"
+)
+
+func TestEventLogWatch(t *testing.T) {
+	data, _ := newTestData(t)
+	events, cleanup := NewEventLog(&conf.Data{}, data, log.DefaultLogger)
+	t.Cleanup(cleanup)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	received := make(chan *namespaced.Event, 10)
+	watching := make(chan error, 1)
+	go func() {
+		watching <- events.Watch(ctx, "test", "", func(event *namespaced.Event) error {
+			received <- event
+			return nil
+		})
+	}()
+	// Wait for the hub to tail the stream.
+	for {
+		events.mu.Lock()
+		_, ok := events.hubs["test"]
+		events.mu.Unlock()
+		if ok {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	want := []*namespaced.Event{
+		{Type: v1.WatchEvent_SET, Key: "a"},
+		{Type: v1.WatchEvent_DELETE, Key: "a"},
+		{Type: v1.WatchEvent_SET, Key: "b"},
+	}
+	for _, event := range want {
+		events.Append(ctx, "test", event)
+	}
+
+	var tokens []string
+	for _, w := range want {
+		select {
+		case got := <-received:
+			if got.Type != w.Type || got.Key != w.Key {
+				t.Fatalf("received %s %q, want %s %q", got.Type, got.Key, w.Type, w.Key)
+			}
+			tokens = append(tokens, got.ResumeToken)
+		case err := <-watching:
+			t.Fatalf("Watch returned %v", err)
+		case <-ctx.Done():
+			t.Fatal("timed out waiting for events")
+		}
+	}
+
+	// Resuming after the first event replays the following ones.
+	resumeCtx, stop := context.WithCancel(ctx)
+	var replayed []string
+	err := events.Watch(resumeCtx, "test", tokens[0], func(event *namespaced.Event) error {
+		replayed = append(replayed, event.ResumeToken)
+		if len(replayed) == len(tokens)-1 {
+			stop()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != 2 || replayed[0] != tokens[1] || replayed[1] != tokens[2] {
+		t.Fatalf("replayed %v, want %v", replayed, tokens[1:])
+	}
+}
+
+func TestEventLogExpiredOnce(t *testing.T) {
+	data, mr := newTestData(t)
+	newInstance := func(id string) *eventLog {
+		return &eventLog{rdb: data.RDB(), log: log.NewHelper(log.DefaultLogger), instance: id, queue: make(chan watchAppend, 10)}
+	}
+	a, b := newInstance("a"), newInstance("b")
+	ctx := context.Background()
+	cacheKey := namespaced.NamespacedKey{Namespace: "test", Key: "k"}.CacheKey()
+
+	// Both instances receive each notification; the key expires twice within
+	// expiredDedupTTL, and the second notification reaches b first.
+	a.handleExpired(ctx, cacheKey)
+	b.handleExpired(ctx, cacheKey)
+	b.handleExpired(ctx, cacheKey)
+	a.handleExpired(ctx, cacheKey)
+	if n := len(a.queue) + len(b.queue); n != 2 {
+		t.Fatalf("%d expire events recorded, want 2", n)
+	}
+
+	// Once the claim has lapsed, any instance may record the next one.
+	mr.FastForward(expiredDedupTTL)
+	b.handleExpired(ctx, cacheKey)
+	a.handleExpired(ctx, cacheKey)
+	if len(b.queue) != 1 || len(a.queue) != 2 {
+		t.Fatalf("a recorded %d and b %d expire events, want 2 and 1", len(a.queue), len(b.queue))
+	}
+}
@@ -0,0 +1,42 @@
+package data
+
+import (
+	"fmt"
+
+	"cacheserver/internal/conf"
+	"cacheserver/pkg/cache"
+)
+
+const (
+	// writePolicyThrough writes the last level of a chain first.
+	writePolicyThrough = "through"
+	// writePolicyAround writes the last level only and invalidates the others.
+	writePolicyAround = "around"
+	// writePolicyBehind queues the writes of the last level in a journal.
+	writePolicyBehind = "behind"
+)
+
+// writeOptions returns the options selecting the configured write policy of
+// a chain. The journal of the "behind" policy is closed with the chain.
+func writeOptions[T any](c *conf.Data_Cache_Write) ([]cache.ChainOption[T], error) {
+	switch c.GetPolicy() {
+	case "", writePolicyThrough:
+		return nil, nil
+	case writePolicyAround:
+		return []cache.ChainOption[T]{cache.WithWritePolicy[T](cache.WriteAround)}, nil
+	case writePolicyBehind:
+		if c.JournalPath == "" {
+			return nil, fmt.Errorf("write policy %q requires a journal path", c.Policy)
+		}
+		journal, err := cache.OpenJournal(c.JournalPath)
+		if err != nil {
+			return nil, err
+		}
+		return []cache.ChainOption[T]{cache.WithWriteBehind[T](journal, cache.WriteBehindConfig{
+			BatchSize:     int(c.BatchSize),
+			FlushInterval: c.FlushInterval.AsDuration(),
+		})}, nil
+	default:
+		return nil, fmt.Errorf("unknown write policy %q", c.GetPolicy())
+	}
+}
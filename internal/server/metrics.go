@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/go-kratos/kratos/v2/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+// metricsPath is where the HTTP server exposes the metrics to Prometheus.
+const metricsPath = "/metrics"
+
+// Metrics is the global OpenTelemetry meter provider, exported in the
+// Prometheus format. Instruments created before it is installed, such as
+// those of the cache chains, report to it as well.
+type Metrics struct {
+	handler http.Handler
+}
+
+// NewMetrics installs the global meter provider.
+func NewMetrics(logger log.Logger) (*Metrics, func(), error) {
+	registry := prometheus.NewRegistry()
+	exporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, nil, err
+	}
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(exporter))
+	otel.SetMeterProvider(provider)
+
+	helper := log.NewHelper(logger)
+	cleanup := func() {
+		if err := provider.Shutdown(context.Background()); err != nil {
+			helper.Errorf("failed to shut down the meter provider: %v", err)
+		}
+	}
+	return &Metrics{handler: promhttp.HandlerFor(registry, promhttp.HandlerOpts{})}, cleanup, nil
+}
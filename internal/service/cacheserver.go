@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	v1 "cacheserver/api/cacheserver/v1"
@@ -36,7 +37,122 @@ func (s *CacheServerService) Del(ctx context.Context, rq *v1.DelRequest) (*empty
 
 // Get retrieves a key's value from the cache by namespace.
 func (s *CacheServerService) Get(ctx context.Context, rq *v1.GetRequest) (*v1.GetResponse, error) {
-	return s.biz.NamespacedV1(rq.Namespace).Get(ctx, rq.Key)
+	return s.biz.NamespacedV1(rq.Namespace).Get(ctx, rq.Key, rq.SlidingExpire)
+}
+
+// Touch resets the TTL of a cache entry without returning its value.
+func (s *CacheServerService) Touch(ctx context.Context, rq *v1.TouchRequest) (*v1.TouchResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).Touch(ctx, rq.Key, rq.Expire)
+}
+
+// CompareAndSet stores a value only if the key's current version matches the supplied one.
+func (s *CacheServerService) CompareAndSet(ctx context.Context, rq *v1.CompareAndSetRequest) (*v1.CompareAndSetResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).CompareAndSet(ctx, rq.Key, rq.Value, rq.Expire, rq.Version)
+}
+
+// CompareAndDelete removes a key only if its current version matches the supplied one.
+func (s *CacheServerService) CompareAndDelete(ctx context.Context, rq *v1.CompareAndDeleteRequest) (*v1.CompareAndDeleteResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).CompareAndDelete(ctx, rq.Key, rq.Version)
+}
+
+// Incr increments a counter by one.
+func (s *CacheServerService) Incr(ctx context.Context, rq *v1.IncrRequest) (*v1.CounterResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).IncrBy(ctx, rq.Key, 1, rq.Expire)
+}
+
+// IncrBy increments a counter by the given delta.
+func (s *CacheServerService) IncrBy(ctx context.Context, rq *v1.IncrByRequest) (*v1.CounterResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).IncrBy(ctx, rq.Key, rq.Delta, rq.Expire)
+}
+
+// Decr decrements a counter by one.
+func (s *CacheServerService) Decr(ctx context.Context, rq *v1.DecrRequest) (*v1.CounterResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).IncrBy(ctx, rq.Key, -1, rq.Expire)
+}
+
+// IncrWithCap increments a counter unless the result would exceed the cap.
+func (s *CacheServerService) IncrWithCap(ctx context.Context, rq *v1.IncrWithCapRequest) (*v1.IncrWithCapResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).IncrWithCap(ctx, rq.Key, rq.Delta, rq.Cap, rq.Expire)
+}
+
+// Watch streams set, delete and expire events for keys of a namespace.
+func (s *CacheServerService) Watch(rq *v1.WatchRequest, stream grpc.ServerStreamingServer[v1.WatchEvent]) error {
+	return s.biz.NamespacedV1(rq.Namespace).Watch(stream.Context(), rq, stream.Send)
+}
+
+// HSet sets fields of a hash value.
+func (s *CacheServerService) HSet(ctx context.Context, rq *v1.HSetRequest) (*v1.HSetResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).HSet(ctx, rq.Key, rq.Fields, rq.Expire)
+}
+
+// HGet retrieves a field of a hash value.
+func (s *CacheServerService) HGet(ctx context.Context, rq *v1.HGetRequest) (*v1.HGetResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).HGet(ctx, rq.Key, rq.Field)
+}
+
+// HDel deletes fields of a hash value.
+func (s *CacheServerService) HDel(ctx context.Context, rq *v1.HDelRequest) (*v1.HDelResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).HDel(ctx, rq.Key, rq.Fields)
+}
+
+// HGetAll retrieves every field of a hash value.
+func (s *CacheServerService) HGetAll(ctx context.Context, rq *v1.HGetAllRequest) (*v1.HGetAllResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).HGetAll(ctx, rq.Key)
+}
+
+// ZAdd adds members to a sorted set value.
+func (s *CacheServerService) ZAdd(ctx context.Context, rq *v1.ZAddRequest) (*v1.ZAddResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).ZAdd(ctx, rq.Key, rq.Members, rq.Expire)
+}
+
+// ZRangeByScore retrieves members of a sorted set value within a score range.
+func (s *CacheServerService) ZRangeByScore(ctx context.Context, rq *v1.ZRangeByScoreRequest) (*v1.ZRangeByScoreResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).ZRangeByScore(ctx, rq.Key, rq.Min, rq.Max, rq.Offset, rq.Count)
+}
+
+// ZRank retrieves the rank of a member of a sorted set value.
+func (s *CacheServerService) ZRank(ctx context.Context, rq *v1.ZRankRequest) (*v1.ZRankResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).ZRank(ctx, rq.Key, rq.Member)
+}
+
+// ZIncrBy increments the score of a member of a sorted set value.
+func (s *CacheServerService) ZIncrBy(ctx context.Context, rq *v1.ZIncrByRequest) (*v1.ZIncrByResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).ZIncrBy(ctx, rq.Key, rq.Member, rq.Delta, rq.Expire)
+}
+
+// LPush prepends values to a list value.
+func (s *CacheServerService) LPush(ctx context.Context, rq *v1.LPushRequest) (*v1.LPushResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).LPush(ctx, rq.Key, rq.Values, rq.Expire)
+}
+
+// RPop removes and returns the last value of a list value.
+func (s *CacheServerService) RPop(ctx context.Context, rq *v1.RPopRequest) (*v1.PopResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).RPop(ctx, rq.Key)
+}
+
+// BRPop is like RPop but waits for a value to arrive.
+func (s *CacheServerService) BRPop(ctx context.Context, rq *v1.BRPopRequest) (*v1.PopResponse, error) {
+	return s.biz.NamespacedV1(rq.Namespace).BRPop(ctx, rq.Key, rq.Timeout)
+}
+
+// RateLimit checks and consumes a distributed rate limit.
+func (s *CacheServerService) RateLimit(ctx context.Context, rq *v1.RateLimitRequest) (*v1.RateLimitResponse, error) {
+	return s.biz.RateLimitV1().Allow(ctx, rq)
+}
+
+// AcquireLock takes a distributed lock, optionally waiting for it to be released.
+func (s *CacheServerService) AcquireLock(ctx context.Context, rq *v1.AcquireLockRequest) (*v1.AcquireLockResponse, error) {
+	return s.biz.LockV1().Acquire(ctx, rq)
+}
+
+// RenewLock extends the lease of a held lock.
+func (s *CacheServerService) RenewLock(ctx context.Context, rq *v1.RenewLockRequest) (*v1.RenewLockResponse, error) {
+	return s.biz.LockV1().Renew(ctx, rq)
+}
+
+// ReleaseLock releases a held lock.
+func (s *CacheServerService) ReleaseLock(ctx context.Context, rq *v1.ReleaseLockRequest) (*v1.ReleaseLockResponse, error) {
+	return s.biz.LockV1().Release(ctx, rq)
 }
 
 // SetSecret stores a secret in the system or updates an existing one.
@@ -53,3 +169,23 @@ func (s *CacheServerService) DelSecret(ctx context.Context, rq *v1.DelSecretRequ
 func (s *CacheServerService) GetSecret(ctx context.Context, rq *v1.GetSecretRequest) (*v1.GetSecretResponse, error) {
 	return s.biz.SecretV1().Get(ctx, rq)
 }
+
+// ListSecrets lists secrets, optionally including soft-deleted ones.
+func (s *CacheServerService) ListSecrets(ctx context.Context, rq *v1.ListSecretsRequest) (*v1.ListSecretsResponse, error) {
+	return s.biz.SecretV1().List(ctx, rq)
+}
+
+// UndeleteSecret restores a soft-deleted secret.
+func (s *CacheServerService) UndeleteSecret(ctx context.Context, rq *v1.UndeleteSecretRequest) (*emptypb.Empty, error) {
+	return s.biz.SecretV1().Undelete(ctx, rq)
+}
+
+// PurgeSecret permanently removes a secret, including soft-deleted copies.
+func (s *CacheServerService) PurgeSecret(ctx context.Context, rq *v1.PurgeSecretRequest) (*emptypb.Empty, error) {
+	return s.biz.SecretV1().Purge(ctx, rq)
+}
+
+// ListAuditEvents lists recorded secret access and mutation events.
+func (s *CacheServerService) ListAuditEvents(ctx context.Context, rq *v1.ListAuditEventsRequest) (*v1.ListAuditEventsResponse, error) {
+	return s.biz.AuditV1().List(ctx, rq)
+}
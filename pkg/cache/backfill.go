@@ -0,0 +1,211 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// BackfillPolicy decides what happens to a backfill when the queue is full.
+// A backfill of a key that is already queued always replaces the queued one.
+type BackfillPolicy int
+
+const (
+	// BackfillDropNew discards the incoming backfill.
+	BackfillDropNew BackfillPolicy = iota
+	// BackfillDropOldest discards the oldest queued backfill to make room.
+	BackfillDropOldest
+)
+
+const (
+	// defaultBackfillWorkers is the default number of backfill workers.
+	defaultBackfillWorkers = 4
+	// defaultBackfillQueueSize is the default number of queued backfills.
+	defaultBackfillQueueSize = 10000
+)
+
+// BackfillConfig configures how values found in a lower level are written
+// back into the levels above it.
+type BackfillConfig struct {
+	// Workers is the number of goroutines writing backfills.
+	Workers int
+	// QueueSize is the maximum number of distinct keys waiting for backfill.
+	QueueSize int
+	// Policy applies when the queue is full.
+	Policy BackfillPolicy
+	// OnError, if set, is called for every level a backfill failed to write.
+	OnError func(key any, level int, err error)
+}
+
+// WithBackfill configures the backfill queue of the chain.
+func WithBackfill[T any](cfg BackfillConfig) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.backfillConfig = cfg
+	}
+}
+
+// backfillItem is a value read from level at generation gen, to write into
+// the levels above it.
+type backfillItem[T any] struct {
+	key   any
+	value T
+	ttl   time.Duration
+	level int
+	gen   uint64
+	read  time.Time
+}
+
+// backfillQueue is a bounded queue of backfills, deduplicated by key and
+// served by a pool of workers. Enqueueing never blocks.
+type backfillQueue[T any] struct {
+	cfg   BackfillConfig
+	apply func(*backfillItem[T])
+
+	mu      sync.Mutex
+	cond    *sync.Cond
+	order   *list.List // of string keys, oldest first
+	pending map[string]*list.Element
+	items   map[string]*backfillItem[T]
+	closed  bool
+	wg      sync.WaitGroup
+
+	enqueued metric.Int64Counter
+}
+
+// newBackfillQueue creates a backfill queue and starts its workers.
+func newBackfillQueue[T any](cfg BackfillConfig, metrics *chainMetrics, apply func(*backfillItem[T])) *backfillQueue[T] {
+	if cfg.Workers <= 0 {
+		cfg.Workers = defaultBackfillWorkers
+	}
+	if cfg.QueueSize <= 0 {
+		cfg.QueueSize = defaultBackfillQueueSize
+	}
+
+	q := &backfillQueue[T]{
+		cfg:      cfg,
+		apply:    apply,
+		order:    list.New(),
+		pending:  make(map[string]*list.Element),
+		items:    make(map[string]*backfillItem[T]),
+		enqueued: metrics.backfills,
+	}
+	q.cond = sync.NewCond(&q.mu)
+
+	q.wg.Add(cfg.Workers)
+	for range cfg.Workers {
+		go q.work()
+	}
+	return q
+}
+
+// push queues a backfill without blocking, coalescing it with a queued
+// backfill of the same key and applying the policy when the queue is full.
+func (q *backfillQueue[T]) push(ctx context.Context, item *backfillItem[T]) {
+	k := keyFunc(item.key)
+
+	q.mu.Lock()
+	outcome := q.pushLocked(k, item)
+	q.mu.Unlock()
+
+	q.enqueued.Add(ctx, 1, metric.WithAttributes(attribute.String("outcome", outcome)))
+}
+
+// pushLocked queues a backfill and returns what happened to it. Callers hold q.mu.
+func (q *backfillQueue[T]) pushLocked(k string, item *backfillItem[T]) string {
+	if q.closed {
+		return "dropped"
+	}
+	if _, ok := q.pending[k]; ok {
+		q.items[k] = item
+		return "coalesced"
+	}
+
+	outcome := "queued"
+	if q.order.Len() >= q.cfg.QueueSize {
+		if q.cfg.Policy != BackfillDropOldest {
+			return "dropped"
+		}
+		oldest := q.order.Remove(q.order.Front()).(string)
+		delete(q.pending, oldest)
+		delete(q.items, oldest)
+		outcome = "replaced_oldest"
+	}
+	q.pending[k] = q.order.PushBack(k)
+	q.items[k] = item
+	q.cond.Signal()
+	return outcome
+}
+
+// work applies queued backfills until the queue is closed and drained.
+func (q *backfillQueue[T]) work() {
+	defer q.wg.Done()
+	for {
+		q.mu.Lock()
+		for q.order.Len() == 0 && !q.closed {
+			q.cond.Wait()
+		}
+		if q.order.Len() == 0 {
+			q.mu.Unlock()
+			return
+		}
+		k := q.order.Remove(q.order.Front()).(string)
+		item := q.items[k]
+		delete(q.pending, k)
+		delete(q.items, k)
+		q.mu.Unlock()
+
+		q.apply(item)
+	}
+}
+
+// close stops accepting backfills and waits until the queued ones are
+// written, or ctx is done.
+func (q *backfillQueue[T]) close(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// backfill writes an item into the levels above the one it was found in,
+// unless the key was written or deleted since it was read.
+func (c *ChainCache[T]) backfill(item *backfillItem[T]) {
+	ctx := context.Background()
+	k := keyFunc(item.key)
+	if c.gens.outdated(k, item.gen, item.read) {
+		c.metrics.backfillDiscards.Add(ctx, 1)
+		return
+	}
+
+	for i := item.level - 1; i >= 0; i-- {
+		if err := c.setLevel(ctx, i, item.key, item.value, item.ttl); err != nil {
+			c.metrics.backfillErrors.Add(ctx, 1, metric.WithAttributes(attribute.Int("level", i)))
+			if c.backfillConfig.OnError != nil {
+				c.backfillConfig.OnError(item.key, i, err)
+			}
+		}
+	}
+
+	if c.gens.outdated(k, item.gen, item.read) {
+		// A write or delete landed while the levels were being written; it
+		// may have been overtaken, so drop what was just written.
+		c.invalidate(ctx, item.key, item.level)
+		c.metrics.backfillDiscards.Add(ctx, 1)
+	}
+}
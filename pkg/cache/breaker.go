@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// ErrCircuitOpen is returned instead of calling a level whose circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("cache level circuit breaker is open")
+
+// BreakerState is the state of a circuit breaker.
+type BreakerState int64
+
+const (
+	// BreakerClosed lets every call through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails every call fast with ErrCircuitOpen.
+	BreakerOpen
+	// BreakerHalfOpen lets a limited number of probe calls through.
+	BreakerHalfOpen
+)
+
+// String returns the name of the state.
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// BreakerConfig configures the circuit breaker of a cache level. Misses are
+// not failures, and neither are calls cancelled by the caller or values the
+// level returned but that could not be decoded.
+type BreakerConfig struct {
+	// ConsecutiveFailures opens the circuit after that many failures in a
+	// row. Zero disables the threshold.
+	ConsecutiveFailures int
+	// ErrorRate opens the circuit when the ratio of failures within Window
+	// reaches it, once at least MinRequests calls were made. Zero disables
+	// the threshold.
+	ErrorRate   float64
+	MinRequests int
+	Window      time.Duration
+	// OpenTimeout is how long the circuit stays open before probing.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe calls let through while half
+	// open. The circuit closes once they all succeed and opens again on the
+	// first failure.
+	HalfOpenProbes int
+}
+
+// DefaultBreakerConfig returns a configuration suited to a remote level.
+func DefaultBreakerConfig() BreakerConfig {
+	return BreakerConfig{
+		ConsecutiveFailures: 5,
+		ErrorRate:           0.5,
+		MinRequests:         20,
+		Window:              10 * time.Second,
+		OpenTimeout:         5 * time.Second,
+		HalfOpenProbes:      3,
+	}
+}
+
+// breaker is a circuit breaker guarding a single cache level.
+type breaker struct {
+	cfg BreakerConfig
+
+	mu          sync.Mutex
+	state       BreakerState
+	consecutive int
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+// newBreaker creates a closed circuit breaker.
+func newBreaker(cfg BreakerConfig) *breaker {
+	return &breaker{cfg: cfg, windowStart: time.Now()}
+}
+
+// State returns the current state of the breaker.
+func (b *breaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= b.cfg.OpenTimeout {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// allow reports whether a call may go through, reserving a probe slot when
+// half open. Every allowed call must be followed by done.
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen {
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return false
+		}
+		b.state, b.probes, b.successes = BreakerHalfOpen, 0, 0
+	}
+	if b.state == BreakerHalfOpen {
+		if b.probes >= max(b.cfg.HalfOpenProbes, 1) {
+			return false
+		}
+		b.probes++
+	}
+	return true
+}
+
+// done records the outcome of a call allowed by allow.
+func (b *breaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	cancelled := errors.Is(err, context.Canceled)
+	failed := !cancelled && isLevelFailure(err)
+
+	if b.state == BreakerHalfOpen {
+		b.probes--
+		switch {
+		case failed:
+			b.open()
+		case !cancelled:
+			b.successes++
+			if b.successes >= max(b.cfg.HalfOpenProbes, 1) {
+				b.close()
+			}
+		}
+		return
+	}
+	if b.state != BreakerClosed || cancelled {
+		return
+	}
+
+	now := time.Now()
+	if b.cfg.Window > 0 && now.Sub(b.windowStart) >= b.cfg.Window {
+		b.windowStart, b.requests, b.failures = now, 0, 0
+	}
+	b.requests++
+	if !failed {
+		b.consecutive = 0
+		return
+	}
+	b.failures++
+	b.consecutive++
+
+	if b.cfg.ConsecutiveFailures > 0 && b.consecutive >= b.cfg.ConsecutiveFailures {
+		b.open()
+		return
+	}
+	if b.cfg.ErrorRate > 0 && b.requests >= b.cfg.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.cfg.ErrorRate {
+		b.open()
+	}
+}
+
+// isLevelFailure reports whether err means that the level itself is
+// failing. A miss or a value of the wrong type or encoding is an answer
+// from a healthy level, about that key only.
+func isLevelFailure(err error) bool {
+	return err != nil &&
+		!errors.Is(err, store.ErrKeyNotFound) &&
+		!errors.Is(err, ErrTypeMismatch) &&
+		!errors.Is(err, ErrDecode)
+}
+
+// open trips the breaker. Callers hold b.mu.
+func (b *breaker) open() {
+	b.state = BreakerOpen
+	b.openedAt = time.Now()
+	b.probes, b.successes = 0, 0
+}
+
+// close resets the breaker. Callers hold b.mu.
+func (b *breaker) close() {
+	b.state = BreakerClosed
+	b.consecutive, b.requests, b.failures = 0, 0, 0
+	b.windowStart = time.Now()
+}
@@ -0,0 +1,39 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+func TestBreakerFailures(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want BreakerState
+	}{
+		{name: "success", want: BreakerClosed},
+		{name: "miss", err: store.ErrKeyNotFound, want: BreakerClosed},
+		{name: "cancelled", err: context.Canceled, want: BreakerClosed},
+		{name: "type mismatch", err: ErrTypeMismatch, want: BreakerClosed},
+		{name: "decode", err: fmt.Errorf("%w: %w", ErrDecode, errors.New("bad json")), want: BreakerClosed},
+		{name: "level failure", err: errors.New("connection refused"), want: BreakerOpen},
+		{name: "deadline", err: context.DeadlineExceeded, want: BreakerOpen},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newBreaker(BreakerConfig{ConsecutiveFailures: 1, OpenTimeout: time.Minute})
+			if !b.allow() {
+				t.Fatal("closed breaker rejected a call")
+			}
+			b.done(tt.err)
+			if got := b.State(); got != tt.want {
+				t.Fatalf("state after %v = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
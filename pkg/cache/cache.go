@@ -28,6 +28,8 @@ type KeyGetter interface {
 }
 
 // DelegateCache is a representative cache used to represent the store.
+// It holds values as they are; a value of another type than T is reported
+// as ErrTypeMismatch.
 type DelegateCache[T any] struct {
 	store store.Store
 }
@@ -48,7 +50,7 @@ func (c *DelegateCache[T]) Get(ctx context.Context, key any) (T, error) {
 		return v, nil
 	}
 
-	return *new(T), nil
+	return *new(T), ErrTypeMismatch
 }
 
 // GetWithTTL returns the obj stored in cache and its corresponding TTL.
@@ -62,7 +64,7 @@ func (c *DelegateCache[T]) GetWithTTL(ctx context.Context, key any) (T, time.Dur
 		return v, duration, nil
 	}
 
-	return *new(T), duration, nil
+	return *new(T), duration, ErrTypeMismatch
 }
 
 // Set populates the cache item using the given key.
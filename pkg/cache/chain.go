@@ -3,60 +3,219 @@ package cache
 import (
 	"context"
 	"errors"
-	"fmt"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+
+	"cacheserver/pkg/cache/store"
+)
+
+const (
+	// refreshTimeout bounds a background refresh of a stale value.
+	refreshTimeout = 10 * time.Second
+	// minFreshnessSweep is the number of tracked keys below which expired
+	// freshness records are not swept.
+	minFreshnessSweep = 1024
 )
 
-// chainKeyValue represents the key-value pair with TTL and cache ID.
-type chainKeyValue[T any] struct {
-	key   any
-	value T
-	ttl   time.Duration
-	id    string
+// Loader loads the value of a key missing from every level, returning it
+// together with its TTL (0 meaning none).
+type Loader[T any] func(ctx context.Context, key any) (T, time.Duration, error)
+
+// Result describes where a value returned by Lookup came from.
+type Result struct {
+	// TTL is the remaining TTL reported by the level that served the value.
+	TTL time.Duration
+	// Level is the index of the level that served the value, or the number
+	// of levels when it came from the loader.
+	Level int
+	// Stale is set when an expired local copy was served because every
+	// lower level failed.
+	Stale bool
 }
 
 type cacheWrapper[T any] struct {
 	Cache[T]
-	id string
+	breaker *breaker // nil when the level has no circuit breaker
+}
+
+// guard runs call unless the circuit breaker of the level is open.
+func (w *cacheWrapper[T]) guard(call func() error) error {
+	if w.breaker == nil {
+		return call()
+	}
+	if !w.breaker.allow() {
+		return ErrCircuitOpen
+	}
+	err := call()
+	w.breaker.done(err)
+	return err
+}
+
+// Get returns the obj stored in the level, failing fast if its circuit is open.
+func (w *cacheWrapper[T]) Get(ctx context.Context, key any) (T, error) {
+	obj, _, err := w.GetWithTTL(ctx, key)
+	return obj, err
+}
+
+// GetWithTTL returns the obj stored in the level and its TTL, failing fast
+// if its circuit is open.
+func (w *cacheWrapper[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
+	var obj T
+	var ttl time.Duration
+	err := w.guard(func() (err error) {
+		obj, ttl, err = w.Cache.GetWithTTL(ctx, key)
+		return err
+	})
+	return obj, ttl, err
+}
+
+// Set stores obj in the level, failing fast if its circuit is open.
+func (w *cacheWrapper[T]) Set(ctx context.Context, key any, obj T) error {
+	return w.guard(func() error { return w.Cache.Set(ctx, key, obj) })
+}
+
+// SetWithTTL stores obj in the level with a TTL, failing fast if its circuit is open.
+func (w *cacheWrapper[T]) SetWithTTL(ctx context.Context, key any, obj T, ttl time.Duration) error {
+	return w.guard(func() error { return w.Cache.SetWithTTL(ctx, key, obj, ttl) })
+}
+
+// Del removes obj from the level, failing fast if its circuit is open.
+func (w *cacheWrapper[T]) Del(ctx context.Context, key any) error {
+	return w.guard(func() error { return w.Cache.Del(ctx, key) })
+}
+
+// freshness records when a value stored through the chain goes stale and
+// when it expires.
+type freshness struct {
+	softExpiry time.Time
+	hardExpiry time.Time // zero when the value has no TTL
+	refreshing bool
 }
 
 // ChainCache represents a chain of caches (multi-level cache).
 type ChainCache[T any] struct {
-	caches     []*cacheWrapper[T]
-	setChannel chan *chainKeyValue[T]
+	caches         []*cacheWrapper[T]
+	backfills      *backfillQueue[T]
+	backfillConfig BackfillConfig
+	gens           *generations
+	tombstoneTTL   time.Duration
+
+	name         string
+	breakers     map[int]BreakerConfig
+	loader       Loader[T]
+	softTTL      time.Duration
+	staleIfError time.Duration
+	loads        singleflight.Group
+	metrics      *chainMetrics
+
+	writePolicy WritePolicy
+	journal     *Journal
+	writeBehind WriteBehindConfig
+	stopFlush   chan struct{}
+	flushDone   chan struct{}
+	closeOnce   sync.Once
+
+	mu        sync.Mutex
+	freshness map[string]*freshness
+	sweepAt   int
+}
+
+// ChainOption configures a ChainCache.
+type ChainOption[T any] func(*ChainCache[T])
+
+// WithName names the chain in its metrics.
+func WithName[T any](name string) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.name = name
+	}
+}
+
+// WithBreaker guards the level at index level with a circuit breaker. While
+// the circuit is open the level is skipped with ErrCircuitOpen instead of
+// waiting for it to time out, and the chain keeps serving from the others.
+func WithBreaker[T any](level int, cfg BreakerConfig) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		if c.breakers == nil {
+			c.breakers = make(map[int]BreakerConfig)
+		}
+		c.breakers[level] = cfg
+	}
+}
+
+// WithLoader sets the loader used when a key misses every level.
+// Concurrent loads of the same key are collapsed into one.
+func WithLoader[T any](loader Loader[T]) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.loader = loader
+	}
+}
+
+// WithSoftTTL makes values older than ttl stale. A stale value is still
+// returned immediately while a single background refresh repopulates it
+// from the levels below the one it was found in, or from the loader. Readers
+// only block once the value has expired from every level (its hard TTL).
+func WithSoftTTL[T any](ttl time.Duration) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.softTTL = ttl
+	}
+}
+
+// WithStaleIfError keeps values in the first level for grace past their TTL.
+// Such an expired copy is never served while a lower level can answer, but
+// it is returned, flagged as stale, when every lower level (and the loader)
+// fails with an error rather than a miss.
+func WithStaleIfError[T any](grace time.Duration) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.staleIfError = grace
+	}
 }
 
 // NewChain instantiates a new cache chain.
 func NewChain[T any](caches ...Cache[T]) *ChainCache[T] {
+	return NewChainWithOptions(caches)
+}
+
+// NewChainWithOptions instantiates a new cache chain with the given options.
+func NewChainWithOptions[T any](caches []Cache[T], opts ...ChainOption[T]) *ChainCache[T] {
 	wrappers := make([]*cacheWrapper[T], 0, len(caches))
 	for _, c := range caches {
-		wrappers = append(wrappers, &cacheWrapper[T]{
-			Cache: c,
-			id:    uuid.New().String(),
-		})
+		wrappers = append(wrappers, &cacheWrapper[T]{Cache: c})
 	}
 	chain := &ChainCache[T]{
-		caches:     wrappers,
-		setChannel: make(chan *chainKeyValue[T], 10000),
+		caches:    wrappers,
+		metrics:   newChainMetrics(),
+		freshness: make(map[string]*freshness),
+		sweepAt:   minFreshnessSweep,
+	}
+	for _, opt := range opts {
+		opt(chain)
 	}
+	for level, cfg := range chain.breakers {
+		if level >= 0 && level < len(wrappers) {
+			wrappers[level].breaker = newBreaker(cfg)
+		}
+	}
+	observeBreakers(chain.name, wrappers)
+	chain.gens = newGenerations(chain.tombstoneTTL)
 
-	go chain.Sync()
+	if chain.writePolicy == WriteBehind && (chain.journal == nil || len(wrappers) == 0) {
+		chain.writePolicy = WriteThrough
+	}
+	if chain.writePolicy == WriteBehind {
+		chain.stopFlush = make(chan struct{})
+		chain.flushDone = make(chan struct{})
+		go chain.flushLoop()
+	} else {
+		chain.journal = nil
+	}
 
-	return chain
-}
+	chain.backfills = newBackfillQueue(chain.backfillConfig, chain.metrics, chain.backfill)
 
-// Sync synchronizes a value in available caches, until a given cache layer.
-func (c *ChainCache[T]) Sync() {
-	for item := range c.setChannel {
-		for _, cache := range c.caches {
-			if item.id == cache.id {
-				break
-			}
-			cache.SetWithTTL(context.Background(), item.key, item.value, item.ttl)
-		}
-	}
+	return chain
 }
 
 // Get returns the obj stored in cache if it exists.
@@ -67,67 +226,120 @@ func (c *ChainCache[T]) Get(ctx context.Context, key any) (T, error) {
 
 // GetWithTTL returns the object and its TTL from the first cache where it exists.
 func (c *ChainCache[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
-	var obj T
+	obj, result, err := c.Lookup(ctx, key)
+	return obj, result.TTL, err
+}
+
+// Lookup returns the object from the first cache where it exists, together
+// with a description of where it was found.
+func (c *ChainCache[T]) Lookup(ctx context.Context, key any) (T, Result, error) {
 	var err error
-	var ttl time.Duration
+	var stale T
+	hasStale, missed := false, false
+	gen, read := c.gens.current(), time.Now()
 
-	for _, cache := range c.caches {
-		obj, ttl, err = cache.GetWithTTL(ctx, key)
-		if err == nil {
+	for i := range c.caches {
+		obj, ttl, getErr := c.getLevel(ctx, i, key)
+		if getErr != nil {
+			err = getErr
+			missed = missed || errors.Is(getErr, store.ErrKeyNotFound)
+			continue
+		}
+		if i == 0 && c.expired(key) {
+			// Only kept for stale-if-error; look for a live copy below.
+			stale, hasStale = obj, true
+			continue
+		}
+
+		// A value can only be refreshed if there is a source below it.
+		refreshable := i+1 < len(c.caches) || c.loader != nil
+		stale := c.softTTL > 0 && refreshable && c.markStale(key, ttl)
+		if i > 0 {
 			// Set the value back until this cache layer (backfill).
-			c.setChannel <- &chainKeyValue[T]{key, obj, ttl, cache.id}
-			return obj, ttl, nil
+			c.backfills.push(ctx, &backfillItem[T]{key: key, value: obj, ttl: ttl, level: i, gen: gen, read: read})
+			if !stale {
+				c.markFresh(key, ttl)
+			}
+		}
+		if stale {
+			c.metrics.staleServes.Add(ctx, 1)
+			go c.refresh(key, i+1)
 		}
+		return obj, Result{TTL: ttl, Level: i}, nil
 	}
 
-	return obj, ttl, err
-}
-
-// Set sets a value in all available caches.
-func (c *ChainCache[T]) Set(ctx context.Context, key any, obj T) error {
-	var errs []error
-	for _, cache := range c.caches {
-		if err := cache.Set(ctx, key, obj); err != nil {
-			errs = append(errs, fmt.Errorf("unable to set item into cache: %w", err))
+	if c.loader != nil {
+		obj, ttl, loadErr := c.load(ctx, key)
+		if loadErr == nil {
+			return obj, Result{TTL: ttl, Level: len(c.caches)}, nil
 		}
+		err = loadErr
+		missed = missed || errors.Is(loadErr, store.ErrKeyNotFound)
 	}
 
-	if len(errs) == 0 {
-		return nil
+	if err == nil {
+		// Only an expired copy was found and there is nothing below it.
+		err = store.ErrKeyNotFound
+		missed = true
 	}
-
-	return errors.Join(errs...)
-}
-
-// SetWithTTL sets a value in all available caches with a specified TTL.
-func (c *ChainCache[T]) SetWithTTL(ctx context.Context, key any, obj T, ttl time.Duration) error {
-	var errs []error
-	for _, cache := range c.caches {
-		if err := cache.SetWithTTL(ctx, key, obj, ttl); err != nil {
-			errs = append(errs, fmt.Errorf("unable to set item into cache: %w", err))
+	if hasStale {
+		if !missed {
+			c.metrics.staleIfErrorServes.Add(ctx, 1)
+			return stale, Result{Level: 0, Stale: true}, nil
 		}
+		// The key is gone below, so the retained copy is of no further use.
+		c.caches[0].Del(ctx, key)
+		c.forget(key)
 	}
+	return *new(T), Result{}, err
+}
 
-	if len(errs) == 0 {
-		return nil
-	}
+// Set sets a value in all available caches according to the write policy.
+func (c *ChainCache[T]) Set(ctx context.Context, key any, obj T) error {
+	return c.write(ctx, key, obj, 0)
+}
 
-	return errors.Join(errs...)
+// SetWithTTL sets a value in all available caches with a specified TTL,
+// according to the write policy.
+func (c *ChainCache[T]) SetWithTTL(ctx context.Context, key any, obj T, ttl time.Duration) error {
+	return c.write(ctx, key, obj, ttl)
 }
 
-// Del removes a value from all available caches.
+// Del removes a value from all available caches according to the write policy.
 func (c *ChainCache[T]) Del(ctx context.Context, key any) error {
-	for _, cache := range c.caches {
-		cache.Del(ctx, key)
-	}
-	return nil
+	return c.remove(ctx, key)
+}
+
+// Close stops the background work of the chain: queued backfills are
+// written and a write-behind journal is flushed once more and closed,
+// bounded by ctx.
+func (c *ChainCache[T]) Close(ctx context.Context) error {
+	var err error
+	c.closeOnce.Do(func() {
+		err = c.backfills.close(ctx)
+		if c.journal == nil {
+			return
+		}
+		close(c.stopFlush)
+		select {
+		case <-c.flushDone:
+			err = errors.Join(err, c.journal.Close())
+		case <-ctx.Done():
+			err = errors.Join(err, ctx.Err())
+		}
+	})
+	return err
 }
 
 // Clear resets all cache data.
 func (c *ChainCache[T]) Clear(ctx context.Context) error {
+	c.gens.advanceAll()
 	for _, cache := range c.caches {
 		cache.Clear(ctx)
 	}
+	c.mu.Lock()
+	c.freshness = make(map[string]*freshness)
+	c.mu.Unlock()
 	return nil
 }
 
@@ -137,3 +349,176 @@ func (c *ChainCache[T]) Wait(ctx context.Context) {
 		cache.Wait(ctx)
 	}
 }
+
+// load calls the loader for a key missing from every level and stores the
+// result in all of them. Concurrent loads of the same key share one call.
+func (c *ChainCache[T]) load(ctx context.Context, key any) (T, time.Duration, error) {
+	type loaded struct {
+		obj T
+		ttl time.Duration
+	}
+
+	result, err, _ := c.loads.Do(keyFunc(key), func() (any, error) {
+		gen, read := c.gens.current(), time.Now()
+		obj, ttl, err := c.loader(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		c.store(ctx, key, obj, ttl, len(c.caches), gen, read)
+		return loaded{obj, ttl}, nil
+	})
+	c.metrics.loads.Add(ctx, 1, metric.WithAttributes(resultAttr(err)))
+	if err != nil {
+		return *new(T), 0, err
+	}
+	value := result.(loaded)
+	return value.obj, value.ttl, nil
+}
+
+// refresh repopulates a stale key from the levels starting at from, falling
+// back to the loader, and stores the value in the levels above the source.
+func (c *ChainCache[T]) refresh(key any, from int) {
+	ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+	defer cancel()
+
+	err := c.refreshFrom(ctx, key, from)
+	c.metrics.refreshes.Add(ctx, 1, metric.WithAttributes(resultAttr(err)))
+
+	c.mu.Lock()
+	if record, ok := c.freshness[keyFunc(key)]; ok {
+		record.refreshing = false
+	}
+	c.mu.Unlock()
+}
+
+// refreshFrom looks a key up in the levels starting at from, then the loader.
+func (c *ChainCache[T]) refreshFrom(ctx context.Context, key any, from int) error {
+	gen, read := c.gens.current(), time.Now()
+	err := errors.New("no source to refresh from")
+	for i := from; i < len(c.caches); i++ {
+		var obj T
+		var ttl time.Duration
+		if obj, ttl, err = c.getLevel(ctx, i, key); err == nil {
+			c.store(ctx, key, obj, ttl, i, gen, read)
+			return nil
+		}
+	}
+
+	if c.loader == nil {
+		return err
+	}
+	obj, ttl, err := c.loader(ctx, key)
+	if err != nil {
+		return err
+	}
+	c.store(ctx, key, obj, ttl, len(c.caches), gen, read)
+	return nil
+}
+
+// store writes a value read at generation gen into the levels above until
+// and marks it fresh, unless the key was written or deleted meanwhile.
+func (c *ChainCache[T]) store(ctx context.Context, key any, obj T, ttl time.Duration, until int, gen uint64, read time.Time) {
+	k := keyFunc(key)
+	if c.gens.outdated(k, gen, read) {
+		c.metrics.backfillDiscards.Add(ctx, 1)
+		return
+	}
+	for i := range c.caches[:until] {
+		c.setLevel(ctx, i, key, obj, ttl)
+	}
+	if c.gens.outdated(k, gen, read) {
+		// A write or delete raced with this one; do not leave the value behind.
+		c.invalidate(ctx, key, until)
+		c.metrics.backfillDiscards.Add(ctx, 1)
+		return
+	}
+	c.markFresh(key, ttl)
+}
+
+// levelTTL returns the TTL to store a value with in level i, extending it
+// by the stale-if-error grace period in the first level.
+func (c *ChainCache[T]) levelTTL(i int, ttl time.Duration) time.Duration {
+	if i == 0 && ttl > 0 {
+		return ttl + c.staleIfError
+	}
+	return ttl
+}
+
+// markFresh records that a value with the given TTL was just stored.
+func (c *ChainCache[T]) markFresh(key any, ttl time.Duration) {
+	if c.softTTL <= 0 && c.staleIfError <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.track(keyFunc(key), ttl, time.Now())
+}
+
+// markStale reports whether a value just read is past its soft TTL and no
+// refresh is running yet, marking it as refreshing if so. Values stored
+// outside this chain are considered fresh from the first time they are read.
+func (c *ChainCache[T]) markStale(key any, ttl time.Duration) bool {
+	k := keyFunc(key)
+	now := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	record, ok := c.freshness[k]
+	if !ok {
+		c.track(k, ttl, now)
+		return false
+	}
+	if record.refreshing || now.Before(record.softExpiry) {
+		return false
+	}
+	record.refreshing = true
+	return true
+}
+
+// track stores a freshness record for a value stored at now, sweeping
+// expired records as the map grows. Callers hold c.mu.
+func (c *ChainCache[T]) track(k string, ttl time.Duration, now time.Time) {
+	record := &freshness{softExpiry: now.Add(c.softTTL)}
+	if ttl > 0 {
+		record.hardExpiry = now.Add(ttl)
+	}
+	c.freshness[k] = record
+
+	if len(c.freshness) >= c.sweepAt {
+		for k, record := range c.freshness {
+			if !record.hardExpiry.IsZero() && now.After(record.hardExpiry.Add(c.staleIfError)) {
+				delete(c.freshness, k)
+			}
+		}
+		c.sweepAt = max(2*len(c.freshness), minFreshnessSweep)
+	}
+}
+
+// expired reports whether the value of a key has passed its TTL and is only
+// retained for stale-if-error.
+func (c *ChainCache[T]) expired(key any) bool {
+	if c.staleIfError <= 0 {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	record, ok := c.freshness[keyFunc(key)]
+	return ok && !record.hardExpiry.IsZero() && time.Now().After(record.hardExpiry)
+}
+
+// forget drops the freshness record of a key.
+func (c *ChainCache[T]) forget(key any) {
+	c.mu.Lock()
+	delete(c.freshness, keyFunc(key))
+	c.mu.Unlock()
+}
+
+// resultAttr returns the metric attribute describing the outcome of err.
+func resultAttr(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("result", "error")
+	}
+	return attribute.String("result", "success")
+}
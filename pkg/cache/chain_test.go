@@ -0,0 +1,443 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// mapCache is an in-memory Cache whose writes of a key can be held at a gate
+// to interleave them deterministically with other operations.
+type mapCache struct {
+	mu     sync.Mutex
+	values map[any]string
+	gates  map[any]*gate
+}
+
+// gate holds the writes of a key: entered is closed once a write reached
+// it and release lets the write through.
+type gate struct {
+	entered chan struct{}
+	release chan struct{}
+	once    sync.Once
+}
+
+func newMapCache(values map[any]string) *mapCache {
+	if values == nil {
+		values = make(map[any]string)
+	}
+	return &mapCache{values: values, gates: make(map[any]*gate)}
+}
+
+// hold makes the next writes of key wait until the returned gate is released.
+func (m *mapCache) hold(key any) *gate {
+	g := &gate{entered: make(chan struct{}), release: make(chan struct{})}
+	m.mu.Lock()
+	m.gates[key] = g
+	m.mu.Unlock()
+	return g
+}
+
+func (m *mapCache) Set(ctx context.Context, key any, obj string) error {
+	return m.SetWithTTL(ctx, key, obj, 0)
+}
+
+func (m *mapCache) SetWithTTL(_ context.Context, key any, obj string, _ time.Duration) error {
+	m.mu.Lock()
+	g := m.gates[key]
+	m.mu.Unlock()
+	if g != nil {
+		g.once.Do(func() { close(g.entered) })
+		<-g.release
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values[key] = obj
+	return nil
+}
+
+func (m *mapCache) Get(ctx context.Context, key any) (string, error) {
+	obj, _, err := m.GetWithTTL(ctx, key)
+	return obj, err
+}
+
+func (m *mapCache) GetWithTTL(_ context.Context, key any) (string, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.values[key]
+	if !ok {
+		return "", 0, store.ErrKeyNotFound
+	}
+	return obj, 0, nil
+}
+
+func (m *mapCache) Del(_ context.Context, key any) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.values, key)
+	return nil
+}
+
+func (m *mapCache) Clear(_ context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.values = make(map[any]string)
+	return nil
+}
+
+func (m *mapCache) Wait(_ context.Context) {}
+
+// failingDel is a mapCache whose deletes fail.
+type failingDel struct {
+	*mapCache
+}
+
+var errDelFailed = errors.New("del failed")
+
+func (f failingDel) Del(context.Context, any) error {
+	return errDelFailed
+}
+
+// lookup returns the value of key and whether it is present.
+func (m *mapCache) lookup(key any) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	obj, ok := m.values[key]
+	return obj, ok
+}
+
+// newTestChain returns a two-level chain with a single backfill worker, so
+// that holding the backfill of one key keeps every later one queued.
+func newTestChain(t *testing.T, l1, l2 *mapCache, opts ...ChainOption[string]) *ChainCache[string] {
+	t.Helper()
+	opts = append([]ChainOption[string]{WithBackfill[string](BackfillConfig{Workers: 1})}, opts...)
+	chain := NewChainWithOptions([]Cache[string]{l1, l2}, opts...)
+	t.Cleanup(func() { chain.Close(context.Background()) })
+	return chain
+}
+
+// mustGet reads key through the chain and checks its value.
+func mustGet(t *testing.T, chain *ChainCache[string], key any, want string) {
+	t.Helper()
+	got, err := chain.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%v): %v", key, err)
+	}
+	if got != want {
+		t.Fatalf("Get(%v) = %q, want %q", key, got, want)
+	}
+}
+
+// drain waits until every queued backfill was applied.
+func drain(t *testing.T, chain *ChainCache[string]) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := chain.Close(ctx); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"k": "v"})
+	chain := newTestChain(t, l1, l2)
+
+	mustGet(t, chain, "k", "v")
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); !ok || got != "v" {
+		t.Fatalf("level 0 holds %q (%v), want the backfilled value", got, ok)
+	}
+}
+
+func TestBackfillQueuedBeforeDelIsDiscarded(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"busy": "x", "k": "v"})
+	chain := newTestChain(t, l1, l2)
+
+	// Occupy the only worker so that the backfill of k stays queued.
+	busy := l1.hold("busy")
+	mustGet(t, chain, "busy", "x")
+	<-busy.entered
+
+	mustGet(t, chain, "k", "v")
+	if err := chain.Del(context.Background(), "k"); err != nil {
+		t.Fatalf("Del: %v", err)
+	}
+	close(busy.release)
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("deleted key resurrected in level 0 with %q", got)
+	}
+}
+
+func TestBackfillRacingDelIsInvalidated(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"k": "v"})
+	chain := newTestChain(t, l1, l2)
+
+	// Hold the backfill after it passed its first check, inside the write.
+	write := l1.hold("k")
+	mustGet(t, chain, "k", "v")
+	<-write.entered
+
+	if err := chain.Del(context.Background(), "k"); err != nil {
+		t.Fatalf("Del: %v", err)
+	}
+	close(write.release)
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("deleted key resurrected in level 0 with %q", got)
+	}
+}
+
+func TestBackfillRacingInvalidateIsDiscarded(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"k": "old"})
+	chain := newTestChain(t, l1, l2)
+
+	write := l1.hold("k")
+	mustGet(t, chain, "k", "old")
+	<-write.entered
+
+	// The last level is changed behind the back of the chain.
+	l2.Del(context.Background(), "k")
+	if err := chain.Invalidate(context.Background(), "k", 1); err != nil {
+		t.Fatalf("Invalidate: %v", err)
+	}
+	close(write.release)
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("invalidated key resurrected in level 0 with %q", got)
+	}
+}
+
+func TestBackfillQueuedBeforeSetIsDiscarded(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"busy": "x", "k": "old"})
+	chain := newTestChain(t, l1, l2)
+
+	busy := l1.hold("busy")
+	mustGet(t, chain, "busy", "x")
+	<-busy.entered
+
+	mustGet(t, chain, "k", "old")
+	if err := chain.Set(context.Background(), "k", "new"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	close(busy.release)
+	drain(t, chain)
+
+	if got, _ := l1.lookup("k"); got != "new" {
+		t.Fatalf("level 0 holds %q, want the value written after the read", got)
+	}
+}
+
+func TestBackfillQueuedBeforeClearIsDiscarded(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"busy": "x", "k": "v"})
+	chain := newTestChain(t, l1, l2)
+
+	busy := l1.hold("busy")
+	mustGet(t, chain, "busy", "x")
+	<-busy.entered
+
+	mustGet(t, chain, "k", "v")
+	if err := chain.Clear(context.Background()); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	close(busy.release)
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("cleared key resurrected in level 0 with %q", got)
+	}
+}
+
+func TestBackfillOlderThanTombstoneIsDiscarded(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"busy": "x", "k": "v"})
+	chain := newTestChain(t, l1, l2, WithTombstoneTTL[string](time.Millisecond))
+
+	busy := l1.hold("busy")
+	mustGet(t, chain, "busy", "x")
+	<-busy.entered
+
+	mustGet(t, chain, "k", "v")
+	// Past the tombstone TTL a delete can no longer be detected.
+	time.Sleep(10 * time.Millisecond)
+	close(busy.release)
+	drain(t, chain)
+
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("backfill older than the tombstone TTL applied with %q", got)
+	}
+}
+
+func TestBackfillConcurrentDel(t *testing.T) {
+	const keys = 64
+
+	values := make(map[any]string, keys)
+	for i := range keys {
+		values[fmt.Sprint(i)] = "v"
+	}
+	l1, l2 := newMapCache(nil), newMapCache(values)
+	chain := NewChainWithOptions([]Cache[string]{l1, l2})
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := range keys {
+		key := fmt.Sprint(i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				chain.Get(ctx, key)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			time.Sleep(time.Millisecond)
+			chain.Del(ctx, key)
+		}()
+	}
+	wg.Wait()
+	drain(t, chain)
+
+	for i := range keys {
+		if got, ok := l1.lookup(fmt.Sprint(i)); ok {
+			t.Fatalf("deleted key %d resurrected in level 0 with %q", i, got)
+		}
+	}
+}
+
+func TestSoftTTLRefresh(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels func(l1 *mapCache) []Cache[string]
+		opts   []ChainOption[string]
+	}{
+		{
+			name: "from the level below",
+			levels: func(l1 *mapCache) []Cache[string] {
+				return []Cache[string]{l1, newMapCache(map[any]string{"k": "new"})}
+			},
+		},
+		{
+			name: "from the loader",
+			levels: func(l1 *mapCache) []Cache[string] {
+				return []Cache[string]{l1}
+			},
+			opts: []ChainOption[string]{WithLoader(func(context.Context, any) (string, time.Duration, error) {
+				return "new", 0, nil
+			})},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l1 := newMapCache(map[any]string{"k": "old"})
+			opts := append([]ChainOption[string]{WithSoftTTL[string](time.Millisecond)}, tt.opts...)
+			chain := NewChainWithOptions(tt.levels(l1), opts...)
+			t.Cleanup(func() { chain.Close(context.Background()) })
+
+			// The first read starts the soft TTL of a value stored elsewhere.
+			mustGet(t, chain, "k", "old")
+			time.Sleep(5 * time.Millisecond)
+
+			// A stale value is served while it is refreshed in the background.
+			mustGet(t, chain, "k", "old")
+			deadline := time.Now().Add(5 * time.Second)
+			for {
+				if got, _ := l1.lookup("k"); got == "new" {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("stale value never refreshed")
+				}
+				time.Sleep(time.Millisecond)
+			}
+			mustGet(t, chain, "k", "new")
+		})
+	}
+}
+
+func TestDelInvalidatesWhenLastLevelFails(t *testing.T) {
+	l1 := newMapCache(map[any]string{"k": "v"})
+	l2 := failingDel{newMapCache(map[any]string{"k": "v"})}
+	chain := NewChainWithOptions([]Cache[string]{l1, l2})
+	t.Cleanup(func() { chain.Close(context.Background()) })
+
+	if err := chain.Del(context.Background(), "k"); !errors.Is(err, errDelFailed) {
+		t.Fatalf("Del = %v, want %v", err, errDelFailed)
+	}
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("level 0 kept %q after a failed delete of the last level", got)
+	}
+}
+
+func TestWriteBehindPendingWrites(t *testing.T) {
+	l1, l2 := newMapCache(nil), newMapCache(map[any]string{"k": "old", "n": "old"})
+	journal, err := OpenJournal(filepath.Join(t.TempDir(), "journal"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := newTestChain(t, l1, l2, WithWriteBehind[string](journal, WriteBehindConfig{FlushInterval: time.Hour}))
+	ctx := context.Background()
+
+	// A pending delete is a miss although the last level still holds the value.
+	if err := chain.Del(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := chain.Get(ctx, "k"); !errors.Is(err, store.ErrKeyNotFound) {
+		t.Fatalf("Get of a pending delete = %q, %v", got, err)
+	}
+
+	// A pending write is served once the levels above lost it.
+	if err := chain.Set(ctx, "n", "new"); err != nil {
+		t.Fatal(err)
+	}
+	l1.Del(ctx, "n")
+	mustGet(t, chain, "n", "new")
+
+	drain(t, chain)
+	if got, ok := l1.lookup("k"); ok {
+		t.Fatalf("deleted key resurrected in level 0 with %q", got)
+	}
+	if got, ok := l2.lookup("k"); ok {
+		t.Fatalf("flush left %q in the last level", got)
+	}
+	if got, _ := l2.lookup("n"); got != "new" {
+		t.Fatalf("last level holds %q, want %q", got, "new")
+	}
+}
+
+func TestSetSource(t *testing.T) {
+	l1, l2 := newMapCache(map[any]string{"k": "upper"}), newMapCache(nil)
+	chain := newTestChain(t, l1, l2)
+	ctx := context.Background()
+
+	// Only the last level is written, the levels above are left as is.
+	if err := chain.SetSource(ctx, "k", "source", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := l2.lookup("k"); got != "source" {
+		t.Fatalf("last level holds %q, want %q", got, "source")
+	}
+	if got, _ := l1.lookup("k"); got != "upper" {
+		t.Fatalf("level 0 holds %q, want %q", got, "upper")
+	}
+
+	if err := chain.DelSource(ctx, "k"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := l2.lookup("k"); ok {
+		t.Fatal("last level kept the key")
+	}
+	if _, ok := l1.lookup("k"); !ok {
+		t.Fatal("level 0 lost the key")
+	}
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+var (
+	// ErrTypeMismatch is returned when the value stored at a key is not of
+	// the type the cache serves.
+	ErrTypeMismatch = errors.New("cached value has an unexpected type")
+	// ErrDecode is returned, wrapped with the cause, when a stored value
+	// cannot be decoded.
+	ErrDecode = errors.New("unable to decode cached value")
+)
+
+// Codec encodes values of type T to bytes and back.
+type Codec[T any] interface {
+	Marshal(obj T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+// JSONCodec is a Codec encoding values as JSON.
+type JSONCodec[T any] struct{}
+
+// Marshal encodes obj as JSON.
+func (JSONCodec[T]) Marshal(obj T) ([]byte, error) {
+	return json.Marshal(obj)
+}
+
+// Unmarshal decodes a JSON value.
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var obj T
+	err := json.Unmarshal(data, &obj)
+	return obj, err
+}
+
+// CodecCache is a cache storing values encoded by a codec, so that stores
+// holding bytes or strings serve typed values. Every read decodes a new
+// value, so callers never share what they get.
+type CodecCache[T any] struct {
+	store store.Store
+	codec Codec[T]
+}
+
+// NewCodec instantiates a new codec-backed cache on the store.
+func NewCodec[T any](store store.Store, codec Codec[T]) *CodecCache[T] {
+	return &CodecCache[T]{store: store, codec: codec}
+}
+
+// Get returns the obj stored in cache if it exists.
+func (c *CodecCache[T]) Get(ctx context.Context, key any) (T, error) {
+	value, err := c.store.Get(ctx, keyFunc(key))
+	if err != nil {
+		return *new(T), err
+	}
+	return c.decode(value)
+}
+
+// GetWithTTL returns the obj stored in cache and its corresponding TTL.
+func (c *CodecCache[T]) GetWithTTL(ctx context.Context, key any) (T, time.Duration, error) {
+	value, duration, err := c.store.GetWithTTL(ctx, keyFunc(key))
+	if err != nil {
+		return *new(T), duration, err
+	}
+	obj, err := c.decode(value)
+	return obj, duration, err
+}
+
+// Set populates the cache item using the given key.
+func (c *CodecCache[T]) Set(ctx context.Context, key any, obj T) error {
+	data, err := c.codec.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return c.store.Set(ctx, keyFunc(key), data)
+}
+
+// SetWithTTL populates the cache item using the given key with a specified TTL.
+func (c *CodecCache[T]) SetWithTTL(ctx context.Context, key any, obj T, ttl time.Duration) error {
+	data, err := c.codec.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	return c.store.SetWithTTL(ctx, keyFunc(key), data, ttl)
+}
+
+// Del removes the cache item using the given key.
+func (c *CodecCache[T]) Del(ctx context.Context, key any) error {
+	return c.store.Del(ctx, keyFunc(key))
+}
+
+// Clear resets all cache data.
+func (c *CodecCache[T]) Clear(ctx context.Context) error {
+	return c.store.Clear(ctx)
+}
+
+// Wait waits for all cache operations to complete.
+func (c *CodecCache[T]) Wait(ctx context.Context) {
+	c.store.Wait(ctx)
+}
+
+// decode decodes a value read from the store, which holds bytes or a string.
+func (c *CodecCache[T]) decode(value any) (T, error) {
+	var data []byte
+	switch typed := value.(type) {
+	case []byte:
+		data = typed
+	case string:
+		data = []byte(typed)
+	default:
+		return *new(T), ErrTypeMismatch
+	}
+
+	obj, err := c.codec.Unmarshal(data)
+	if err != nil {
+		return *new(T), fmt.Errorf("%w: %w", ErrDecode, err)
+	}
+	return obj, nil
+}
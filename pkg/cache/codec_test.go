@@ -0,0 +1,63 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	memorystore "cacheserver/pkg/cache/store/memory"
+)
+
+func TestCodecCacheDecode(t *testing.T) {
+	type value struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		name   string
+		stored any
+		want   value
+		err    error
+	}{
+		{"bytes", []byte(`{"name":"a"}`), value{Name: "a"}, nil},
+		{"string", `{"name":"b"}`, value{Name: "b"}, nil},
+		{"other type", 42, value{}, ErrTypeMismatch},
+		{"struct", value{Name: "c"}, value{}, ErrTypeMismatch},
+		{"invalid json", "{", value{}, ErrDecode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := memorystore.NewMemory(memorystore.Options{})
+			defer s.Close()
+			c := NewCodec[value](s, JSONCodec[value]{})
+			ctx := context.Background()
+
+			if err := s.Set(ctx, "key", tt.stored); err != nil {
+				t.Fatal(err)
+			}
+			got, err := c.Get(ctx, "key")
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Fatalf("Get() = %+v, want %+v", got, tt.want)
+			}
+			if _, _, err := c.GetWithTTL(ctx, "key"); !errors.Is(err, tt.err) {
+				t.Fatalf("GetWithTTL() error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+
+	// A typed chain reports a mismatch rather than a miss, so that callers can
+	// tell a key holding another type apart.
+	s := memorystore.NewMemory(memorystore.Options{})
+	defer s.Close()
+	chain := NewChainWithOptions([]Cache[value]{NewCodec[value](s, JSONCodec[value]{})})
+	defer chain.Close(context.Background())
+	if err := s.Set(context.Background(), "key", 42); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := chain.Get(context.Background(), "key"); !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("chain Get() error = %v, want %v", err, ErrTypeMismatch)
+	}
+}
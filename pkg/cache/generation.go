@@ -0,0 +1,127 @@
+package cache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// defaultTombstoneTTL is how long the generation of a written or deleted
+// key is remembered by default.
+const defaultTombstoneTTL = 30 * time.Second
+
+// WithTombstoneTTL sets how long the chain remembers that a key was written
+// or deleted, to discard backfills and refreshes read before. Those that
+// take longer than ttl to be applied are discarded as well.
+func WithTombstoneTTL[T any](ttl time.Duration) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.tombstoneTTL = ttl
+	}
+}
+
+// stamp is the generation of the last write or delete of a key.
+type stamp struct {
+	gen uint64
+	at  time.Time
+}
+
+// generations orders the writes and deletes of a chain against the values
+// it copies asynchronously into its upper levels. A value read at an older
+// generation than the last write or delete of its key is outdated.
+type generations struct {
+	ttl   time.Duration
+	clock atomic.Uint64
+
+	mu      sync.Mutex
+	stamps  map[string]stamp
+	cleared uint64
+	sweepAt int
+}
+
+// newGenerations creates generations remembering stamps for ttl.
+func newGenerations(ttl time.Duration) *generations {
+	if ttl <= 0 {
+		ttl = defaultTombstoneTTL
+	}
+	return &generations{ttl: ttl, stamps: make(map[string]stamp), sweepAt: minFreshnessSweep}
+}
+
+// current returns the generation to capture before reading a value.
+func (g *generations) current() uint64 {
+	return g.clock.Load()
+}
+
+// advance stamps a key with a new generation, sweeping stamps older than
+// g.ttl as the map grows.
+func (g *generations) advance(k string) {
+	now := time.Now()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stamps[k] = stamp{gen: g.clock.Add(1), at: now}
+
+	if len(g.stamps) >= g.sweepAt {
+		for k, s := range g.stamps {
+			if now.Sub(s.at) >= g.ttl {
+				delete(g.stamps, k)
+			}
+		}
+		g.sweepAt = max(2*len(g.stamps), minFreshnessSweep)
+	}
+}
+
+// advanceAll stamps every key with a new generation.
+func (g *generations) advanceAll() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.cleared = g.clock.Add(1)
+	g.stamps = make(map[string]stamp)
+	g.sweepAt = minFreshnessSweep
+}
+
+// outdated reports whether a value of a key read at generation gen at time
+// read may have been overwritten or deleted since. Once g.ttl has passed the
+// stamp may be gone, so the value is considered outdated.
+func (g *generations) outdated(k string, gen uint64, read time.Time) bool {
+	if time.Since(read) >= g.ttl {
+		return true
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if gen < g.cleared {
+		return true
+	}
+	s, ok := g.stamps[k]
+	return ok && s.gen > gen
+}
+
+// Generations orders the writes and deletes of keys against the values
+// copied asynchronously into a cache outside of a chain, the way a chain
+// does for its backfills: capture Current before reading a value, and only
+// keep the copy if the key is not Outdated both before and after storing it.
+type Generations struct {
+	g *generations
+}
+
+// NewGenerations creates generations remembering writes for ttl, or
+// defaultTombstoneTTL if ttl is not positive.
+func NewGenerations(ttl time.Duration) *Generations {
+	return &Generations{g: newGenerations(ttl)}
+}
+
+// Current returns the generation to capture before reading a value.
+func (g *Generations) Current() uint64 {
+	return g.g.current()
+}
+
+// Advance records that key was written or deleted.
+func (g *Generations) Advance(key string) {
+	g.g.advance(key)
+}
+
+// Outdated reports whether a value of key read at generation gen at time
+// read may have been overwritten or deleted since.
+func (g *Generations) Outdated(key string, gen uint64, read time.Time) bool {
+	return g.g.outdated(key, gen, read)
+}
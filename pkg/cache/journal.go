@@ -0,0 +1,225 @@
+package cache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+const (
+	// journalOpSet records a write of a value.
+	journalOpSet = "set"
+	// journalOpDel records a delete.
+	journalOpDel = "del"
+	// minJournalCompaction is the number of records below which the journal
+	// file is never rewritten.
+	minJournalCompaction = 1024
+)
+
+// journalEntry is a write waiting to be applied to the last level.
+type journalEntry struct {
+	Seq       uint64 `json:"seq"`
+	Op        string `json:"op"`
+	Key       string `json:"key"`
+	Value     []byte `json:"value,omitempty"`
+	ExpiresAt int64  `json:"expires_at,omitempty"` // unix nanoseconds, 0 for none
+}
+
+// Journal is a durable queue of pending writes, backed by an append-only
+// file. Writes to the same key are coalesced, so only the latest one of each
+// key is applied.
+type Journal struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	seq     uint64
+	records int
+	pending map[string]*journalEntry
+}
+
+// OpenJournal opens the journal at path, creating it if needed, and reloads
+// the writes that were not applied yet.
+func OpenJournal(path string) (*Journal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, err
+	}
+
+	j := &Journal{path: path, file: file, pending: make(map[string]*journalEntry)}
+	torn, err := j.replay()
+	if err == nil && torn {
+		// Rewrite the file so that new records do not follow the torn one.
+		err = j.compact()
+	}
+	if err != nil {
+		j.file.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// replay rebuilds the pending writes from the journal file and reports
+// whether it ended with a torn record, left by a crash during append.
+func (j *Journal) replay() (bool, error) {
+	if _, err := j.file.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(j.file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		var entry journalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return true, nil
+		}
+		j.records++
+		j.seq = max(j.seq, entry.Seq)
+		if entry.Op == "" {
+			// An acknowledgement of an applied write.
+			if current, ok := j.pending[entry.Key]; ok && current.Seq <= entry.Seq {
+				delete(j.pending, entry.Key)
+			}
+			continue
+		}
+		j.pending[entry.Key] = &entry
+	}
+	return false, scanner.Err()
+}
+
+// Append durably records a write.
+func (j *Journal) Append(op, key string, value []byte, expiresAt int64) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.seq++
+	entry := &journalEntry{Seq: j.seq, Op: op, Key: key, Value: value, ExpiresAt: expiresAt}
+	if err := j.write(entry); err != nil {
+		return err
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	j.pending[key] = entry
+	return nil
+}
+
+// Len returns the number of pending writes.
+func (j *Journal) Len() int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return len(j.pending)
+}
+
+// pendingEntry returns the pending write of a key, if any.
+func (j *Journal) pendingEntry(key string) (journalEntry, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entry, ok := j.pending[key]
+	if !ok {
+		return journalEntry{}, false
+	}
+	return *entry, true
+}
+
+// batch returns up to n pending writes, oldest first.
+func (j *Journal) batch(n int) []journalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]journalEntry, 0, len(j.pending))
+	for _, entry := range j.pending {
+		entries = append(entries, *entry)
+	}
+	sort.Slice(entries, func(a, b int) bool { return entries[a].Seq < entries[b].Seq })
+	if len(entries) > n {
+		entries = entries[:n]
+	}
+	return entries
+}
+
+// ack marks writes as applied. A write superseded by a newer one of the same
+// key stays pending.
+func (j *Journal) ack(entries []journalEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for _, entry := range entries {
+		if current, ok := j.pending[entry.Key]; ok && current.Seq == entry.Seq {
+			delete(j.pending, entry.Key)
+			if err := j.write(&journalEntry{Seq: entry.Seq, Key: entry.Key}); err != nil {
+				return err
+			}
+		}
+	}
+	if err := j.file.Sync(); err != nil {
+		return err
+	}
+	if j.records >= max(minJournalCompaction, 4*len(j.pending)) {
+		return j.compact()
+	}
+	return nil
+}
+
+// write appends a record to the journal file. Callers hold j.mu and sync
+// the file afterwards.
+func (j *Journal) write(entry *journalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	j.records++
+	return nil
+}
+
+// compact rewrites the journal file with the pending writes only. Callers
+// hold j.mu.
+func (j *Journal) compact() error {
+	tmp := j.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	for _, entry := range j.pending {
+		data, err := json.Marshal(entry)
+		if err == nil {
+			_, err = writer.Write(append(data, '\n'))
+		}
+		if err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if err := errors.Join(writer.Flush(), file.Sync(), file.Close()); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, j.path); err != nil {
+		return err
+	}
+
+	reopened, err := os.OpenFile(j.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("reopen compacted journal: %w", err)
+	}
+	j.file.Close()
+	j.file = reopened
+	j.records = len(j.pending)
+	return nil
+}
+
+// Close closes the journal file. Pending writes are kept for the next open.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
@@ -0,0 +1,94 @@
+package cache
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+// meterName is the instrumentation scope of the cache metrics.
+const meterName = "cacheserver/pkg/cache"
+
+// chainMetrics holds the instruments recorded by a ChainCache.
+type chainMetrics struct {
+	staleServes        metric.Int64Counter
+	staleIfErrorServes metric.Int64Counter
+	refreshes          metric.Int64Counter
+	loads              metric.Int64Counter
+	writeBehindWrites  metric.Int64Counter
+	backfills          metric.Int64Counter
+	backfillErrors     metric.Int64Counter
+	backfillDiscards   metric.Int64Counter
+}
+
+// newChainMetrics creates the chain instruments from the global meter provider.
+func newChainMetrics() *chainMetrics {
+	meter := otel.Meter(meterName)
+	return &chainMetrics{
+		staleServes: int64Counter(meter, "cache.chain.stale_serves",
+			"Number of reads served a value past its soft TTL."),
+		staleIfErrorServes: int64Counter(meter, "cache.chain.stale_if_error_serves",
+			"Number of reads served an expired value because every lower level failed."),
+		refreshes: int64Counter(meter, "cache.chain.refreshes",
+			"Number of background refreshes of stale values, by result."),
+		loads: int64Counter(meter, "cache.chain.loads",
+			"Number of blocking loads after a miss on every level, by result."),
+		writeBehindWrites: int64Counter(meter, "cache.chain.write_behind_writes",
+			"Number of journaled writes applied to the last level, by result."),
+		backfills: int64Counter(meter, "cache.chain.backfills",
+			"Number of backfills offered to the queue, by outcome."),
+		backfillErrors: int64Counter(meter, "cache.chain.backfill_errors",
+			"Number of failed backfill writes, by level."),
+		backfillDiscards: int64Counter(meter, "cache.chain.backfill_discards",
+			"Number of backfills and refreshes discarded because the key was written or deleted meanwhile."),
+	}
+}
+
+// int64Counter creates a counter, falling back to a no-op one if the meter
+// rejects it so that metrics never break caching.
+func int64Counter(meter metric.Meter, name, description string) metric.Int64Counter {
+	counter, err := meter.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		counter, _ = noop.NewMeterProvider().Meter(meterName).Int64Counter(name)
+	}
+	return counter
+}
+
+// observeBreakers reports the state of the circuit breakers of a chain as a
+// gauge (0 closed, 1 open, 2 half open), labelled with the chain name and
+// level index.
+func observeBreakers[T any](name string, levels []*cacheWrapper[T]) {
+	type observed struct {
+		attrs   metric.MeasurementOption
+		breaker *breaker
+	}
+	var breakers []observed
+	for i, level := range levels {
+		if level.breaker != nil {
+			breakers = append(breakers, observed{
+				attrs:   metric.WithAttributes(attribute.String("chain", name), attribute.Int("level", i)),
+				breaker: level.breaker,
+			})
+		}
+	}
+	if len(breakers) == 0 {
+		return
+	}
+
+	meter := otel.Meter(meterName)
+	gauge, err := meter.Int64ObservableGauge("cache.chain.breaker_state",
+		metric.WithDescription("State of the circuit breaker of a cache level: 0 closed, 1 open, 2 half open."))
+	if err != nil {
+		return
+	}
+	// The registration lives as long as the process, like the chain itself.
+	_, _ = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for _, b := range breakers {
+			o.ObserveInt64(gauge, int64(b.breaker.State()), b.attrs)
+		}
+		return nil
+	}, gauge)
+}
@@ -0,0 +1,402 @@
+package bolt
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.etcd.io/bbolt"
+
+	"cacheserver/pkg/cache/store"
+)
+
+const (
+	// defaultCompactionInterval is the default delay between purges of
+	// expired entries.
+	defaultCompactionInterval = time.Minute
+	// compactionBatch is the number of expired entries purged per transaction,
+	// so that a purge never holds the writer lock for long.
+	compactionBatch = 1000
+	// headerSize is the size of the header stored before every value: the
+	// expiry in unix nanoseconds and the write sequence.
+	headerSize = 16
+)
+
+var (
+	// bucketValues maps keys to a header followed by the value.
+	bucketValues = []byte("values")
+	// bucketExpiry indexes keys with a TTL by expiry, then key.
+	bucketExpiry = []byte("expiry")
+	// bucketOrder indexes keys by write sequence, oldest first, for eviction.
+	bucketOrder = []byte("order")
+	// bucketMeta holds the size accounting.
+	bucketMeta = []byte("meta")
+	// metaSize is the total size of the stored keys and values.
+	metaSize = []byte("size")
+
+	buckets = [][]byte{bucketValues, bucketExpiry, bucketOrder, bucketMeta}
+)
+
+// Options configures a BoltStore.
+type Options struct {
+	// MaxSize bounds the total size of the stored keys and values, in bytes.
+	// The least recently written entries are evicted to stay under it. Zero
+	// means no limit.
+	MaxSize int64
+	// MaxTTL bounds how long an entry is kept, including entries set without
+	// a TTL. Zero means no limit.
+	MaxTTL time.Duration
+	// CompactionInterval is the delay between purges of expired entries.
+	CompactionInterval time.Duration
+	// OnError, if set, is called when a background purge fails.
+	OnError func(err error)
+}
+
+// BoltStore is a persistent store backed by an embedded bbolt database.
+// Values must be strings or byte slices and are returned as byte slices.
+type BoltStore struct {
+	db   *bbolt.DB
+	opts Options
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewBolt opens, or creates, the bbolt database at path and starts purging
+// its expired entries in the background.
+func NewBolt(path string, opts Options) (*BoltStore, error) {
+	if opts.CompactionInterval <= 0 {
+		opts.CompactionInterval = defaultCompactionInterval
+	}
+
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	s := &BoltStore{
+		db:   db,
+		opts: opts,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.compactLoop()
+	return s, nil
+}
+
+// Get returns data stored from a given key.
+func (s *BoltStore) Get(ctx context.Context, key any) (any, error) {
+	value, _, err := s.GetWithTTL(ctx, key)
+	return value, err
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL.
+func (s *BoltStore) GetWithTTL(_ context.Context, key any) (any, time.Duration, error) {
+	var (
+		value []byte
+		ttl   time.Duration
+	)
+	err := s.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(bucketValues).Get(keyBytes(key))
+		if data == nil {
+			return store.ErrKeyNotFound
+		}
+		expiresAt, _ := decodeHeader(data)
+		if expiresAt > 0 {
+			if ttl = time.Until(time.Unix(0, expiresAt)); ttl <= 0 {
+				// Expired, waiting for the next purge.
+				return store.ErrKeyNotFound
+			}
+		}
+		// The data is only valid within the transaction.
+		value = append([]byte(nil), data[headerSize:]...)
+		return nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return value, ttl, nil
+}
+
+// Set defines data in the store for given key identifier.
+func (s *BoltStore) Set(ctx context.Context, key any, value any) error {
+	return s.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL defines data in the store for given key identifier with TTL.
+// A ttl of 0 means no expiration, within MaxTTL.
+func (s *BoltStore) SetWithTTL(_ context.Context, key any, value any, ttl time.Duration) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported value type %T for key '%v'", value, key)
+	}
+	if s.opts.MaxTTL > 0 && (ttl <= 0 || ttl > s.opts.MaxTTL) {
+		ttl = s.opts.MaxTTL
+	}
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	k := keyBytes(key)
+	size := int64(len(k) + len(data))
+	if s.opts.MaxSize > 0 && size > s.opts.MaxSize {
+		return fmt.Errorf("value for key '%v' is larger than the store", key)
+	}
+
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		delta, err := remove(tx, k)
+		if err != nil {
+			return err
+		}
+
+		order := tx.Bucket(bucketOrder)
+		seq, err := order.NextSequence()
+		if err != nil {
+			return err
+		}
+		entry := make([]byte, headerSize+len(data))
+		binary.BigEndian.PutUint64(entry[:8], uint64(expiresAt))
+		binary.BigEndian.PutUint64(entry[8:headerSize], seq)
+		copy(entry[headerSize:], data)
+		if err := tx.Bucket(bucketValues).Put(k, entry); err != nil {
+			return err
+		}
+		if err := order.Put(indexKey(seq, k), nil); err != nil {
+			return err
+		}
+		if expiresAt > 0 {
+			if err := tx.Bucket(bucketExpiry).Put(indexKey(uint64(expiresAt), k), nil); err != nil {
+				return err
+			}
+		}
+
+		total, err := addSize(tx, size-delta)
+		if err != nil {
+			return err
+		}
+		if s.opts.MaxSize > 0 && total > s.opts.MaxSize {
+			return evict(tx, total-s.opts.MaxSize)
+		}
+		return nil
+	})
+}
+
+// Del removes data from the store for given key identifier.
+func (s *BoltStore) Del(_ context.Context, key any) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		size, err := remove(tx, keyBytes(key))
+		if err != nil || size == 0 {
+			return err
+		}
+		_, err = addSize(tx, -size)
+		return err
+	})
+}
+
+// Clear resets all data in the store.
+func (s *BoltStore) Clear(_ context.Context) error {
+	return s.db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+			if _, err := tx.CreateBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Wait waits for all operations to complete.
+func (s *BoltStore) Wait(_ context.Context) {}
+
+// Size returns the total size of the stored keys and values, expired
+// entries not purged yet included.
+func (s *BoltStore) Size() int64 {
+	var size int64
+	s.db.View(func(tx *bbolt.Tx) error {
+		size = readSize(tx)
+		return nil
+	})
+	return size
+}
+
+// Close stops the background purge and closes the database.
+func (s *BoltStore) Close() error {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+	return s.db.Close()
+}
+
+// compactLoop purges expired entries until the store is closed.
+func (s *BoltStore) compactLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.CompactionInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			if err := s.Compact(); err != nil && s.opts.OnError != nil {
+				s.opts.OnError(err)
+			}
+		}
+	}
+}
+
+// Compact purges the expired entries, in batches.
+func (s *BoltStore) Compact() error {
+	for {
+		select {
+		case <-s.stop:
+			return nil
+		default:
+		}
+
+		var purged int
+		err := s.db.Update(func(tx *bbolt.Tx) error {
+			now := uint64(time.Now().UnixNano())
+			var keys [][]byte
+			cursor := tx.Bucket(bucketExpiry).Cursor()
+			for index, _ := cursor.First(); index != nil && len(keys) < compactionBatch; index, _ = cursor.Next() {
+				if binary.BigEndian.Uint64(index[:8]) > now {
+					break
+				}
+				keys = append(keys, append([]byte(nil), index[8:]...))
+			}
+
+			var freed int64
+			for _, k := range keys {
+				size, err := remove(tx, k)
+				if err != nil {
+					return err
+				}
+				freed += size
+			}
+			purged = len(keys)
+			_, err := addSize(tx, -freed)
+			return err
+		})
+		if err != nil || purged < compactionBatch {
+			return err
+		}
+	}
+}
+
+// remove deletes a key and its index entries, returning the size it freed.
+func remove(tx *bbolt.Tx, k []byte) (int64, error) {
+	values := tx.Bucket(bucketValues)
+	data := values.Get(k)
+	if data == nil {
+		return 0, nil
+	}
+	expiresAt, seq := decodeHeader(data)
+	size := int64(len(k) + len(data) - headerSize)
+
+	if err := tx.Bucket(bucketOrder).Delete(indexKey(seq, k)); err != nil {
+		return 0, err
+	}
+	if expiresAt > 0 {
+		if err := tx.Bucket(bucketExpiry).Delete(indexKey(uint64(expiresAt), k)); err != nil {
+			return 0, err
+		}
+	}
+	return size, values.Delete(k)
+}
+
+// evict removes the least recently written entries until at least excess
+// bytes are freed.
+func evict(tx *bbolt.Tx, excess int64) error {
+	var (
+		keys  [][]byte
+		freed int64
+	)
+	values := tx.Bucket(bucketValues)
+	cursor := tx.Bucket(bucketOrder).Cursor()
+	for index, _ := cursor.First(); index != nil && freed < excess; index, _ = cursor.Next() {
+		k := append([]byte(nil), index[8:]...)
+		freed += int64(len(k) + len(values.Get(k)) - headerSize)
+		keys = append(keys, k)
+	}
+
+	// The cursor is not used past this point, as deleting moves it.
+	for _, k := range keys {
+		if _, err := remove(tx, k); err != nil {
+			return err
+		}
+	}
+	_, err := addSize(tx, -freed)
+	return err
+}
+
+// addSize adjusts the total size by delta and returns the new total.
+func addSize(tx *bbolt.Tx, delta int64) (int64, error) {
+	total := max(readSize(tx)+delta, 0)
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, uint64(total))
+	return total, tx.Bucket(bucketMeta).Put(metaSize, buf)
+}
+
+// readSize returns the total size of the stored keys and values.
+func readSize(tx *bbolt.Tx) int64 {
+	data := tx.Bucket(bucketMeta).Get(metaSize)
+	if len(data) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(data))
+}
+
+// decodeHeader returns the expiry and write sequence of a stored value.
+func decodeHeader(data []byte) (int64, uint64) {
+	if len(data) < headerSize {
+		return 0, 0
+	}
+	return int64(binary.BigEndian.Uint64(data[:8])), binary.BigEndian.Uint64(data[8:headerSize])
+}
+
+// indexKey returns the key of an index entry: n, big endian so that entries
+// sort by it, followed by the key.
+func indexKey(n uint64, k []byte) []byte {
+	index := make([]byte, 8+len(k))
+	binary.BigEndian.PutUint64(index, n)
+	copy(index[8:], k)
+	return index
+}
+
+// keyBytes returns the stored form of a key.
+func keyBytes(key any) []byte {
+	switch k := key.(type) {
+	case string:
+		return []byte(k)
+	case []byte:
+		return k
+	default:
+		return []byte(fmt.Sprint(key))
+	}
+}
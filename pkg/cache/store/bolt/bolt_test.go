@@ -0,0 +1,144 @@
+package bolt
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// newTestBolt opens a store in a temporary directory.
+func newTestBolt(t *testing.T, opts Options) *BoltStore {
+	t.Helper()
+
+	s, err := NewBolt(filepath.Join(t.TempDir(), "cache.db"), opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestBoltStoreTTL(t *testing.T) {
+	tests := []struct {
+		name   string
+		maxTTL time.Duration
+		ttl    time.Duration
+		// want is the TTL read back, within a second; zero for none.
+		want time.Duration
+	}{
+		{"no ttl", 0, 0, 0},
+		{"ttl", 0, time.Hour, time.Hour},
+		{"ttl within max", time.Hour, time.Minute, time.Minute},
+		{"ttl above max", time.Minute, time.Hour, time.Minute},
+		{"no ttl with max", time.Minute, 0, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestBolt(t, Options{MaxTTL: tt.maxTTL})
+			ctx := context.Background()
+
+			if err := s.SetWithTTL(ctx, "key", "value", tt.ttl); err != nil {
+				t.Fatal(err)
+			}
+			value, ttl, err := s.GetWithTTL(ctx, "key")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(value.([]byte)) != "value" {
+				t.Fatalf("value = %q, want %q", value, "value")
+			}
+			if ttl > tt.want || ttl < tt.want-time.Second {
+				t.Fatalf("ttl = %v, want %v", ttl, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoltStoreCompact(t *testing.T) {
+	s := newTestBolt(t, Options{CompactionInterval: time.Hour})
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if err := s.SetWithTTL(ctx, key, "v", time.Millisecond); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Set(ctx, "kept", "v"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	// Expired entries are never served, and only freed by a purge.
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, store.ErrKeyNotFound) {
+		t.Fatalf("Get(expired) = %v, want %v", err, store.ErrKeyNotFound)
+	}
+	if want := 3*len64("a", "v") + len64("kept", "v"); s.Size() != want {
+		t.Fatalf("Size() before purge = %d, want %d", s.Size(), want)
+	}
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Size(); got != len64("kept", "v") {
+		t.Fatalf("Size() after purge = %d, want %d", got, len64("kept", "v"))
+	}
+	if _, err := s.Get(ctx, "kept"); err != nil {
+		t.Fatalf("Get(kept): %v", err)
+	}
+}
+
+func TestBoltStoreMaxSize(t *testing.T) {
+	s := newTestBolt(t, Options{MaxSize: 10})
+	ctx := context.Background()
+
+	steps := []struct {
+		name  string
+		key   string
+		value string
+		err   bool
+		size  int64
+		kept  []string
+		lost  []string
+	}{
+		{"first", "a", "1234", false, 5, []string{"a"}, nil},
+		{"fill", "b", "1234", false, 10, []string{"a", "b"}, nil},
+		{"evict oldest", "c", "1234", false, 10, []string{"b", "c"}, []string{"a"}},
+		{"overwrite", "b", "1", false, 7, []string{"b", "c"}, nil},
+		{"evict several", "d", "12345678", false, 9, []string{"d"}, []string{"b", "c"}},
+		{"too large", "e", "1234567890", true, 9, []string{"d"}, []string{"e"}},
+	}
+	for _, step := range steps {
+		err := s.Set(ctx, step.key, step.value)
+		if (err != nil) != step.err {
+			t.Fatalf("%s: Set = %v, want error %v", step.name, err, step.err)
+		}
+		if got := s.Size(); got != step.size {
+			t.Fatalf("%s: Size() = %d, want %d", step.name, got, step.size)
+		}
+		for _, key := range step.kept {
+			if _, err := s.Get(ctx, key); err != nil {
+				t.Fatalf("%s: Get(%q): %v", step.name, key, err)
+			}
+		}
+		for _, key := range step.lost {
+			if _, err := s.Get(ctx, key); !errors.Is(err, store.ErrKeyNotFound) {
+				t.Fatalf("%s: Get(%q) = %v, want evicted", step.name, key, err)
+			}
+		}
+	}
+
+	if err := s.Del(ctx, "d"); err != nil {
+		t.Fatal(err)
+	}
+	if got := s.Size(); got != 0 {
+		t.Fatalf("Size() after Del = %d, want 0", got)
+	}
+}
+
+// len64 returns the size accounted for a key and its value.
+func len64(key, value string) int64 {
+	return int64(len(key) + len(value))
+}
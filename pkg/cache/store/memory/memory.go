@@ -0,0 +1,271 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// ErrTooLarge is returned when a value is larger than a shard of the store
+// and can never be held.
+var ErrTooLarge = errors.New("value is larger than the store")
+
+// Policy selects which entry is evicted when the store is full.
+type Policy int
+
+const (
+	// LRU evicts the least recently used entry.
+	LRU Policy = iota
+	// LFU evicts the least frequently used entry, the least recently used
+	// first among equals.
+	LFU
+)
+
+const (
+	// defaultShards is the default number of independently locked shards.
+	defaultShards = 16
+	// defaultTick is the default resolution of the expiry wheel.
+	defaultTick = 100 * time.Millisecond
+	// wheelSlots is the number of slots of the expiry wheel. Entries expiring
+	// further away than a full turn stay in their slot until their turn.
+	wheelSlots = 512
+	// defaultValueSize is the size accounted for values of unknown types.
+	defaultValueSize = 64
+)
+
+// Options configures a MemoryStore.
+type Options struct {
+	// Policy is the eviction policy.
+	Policy Policy
+	// MaxSize bounds the total size of the stored keys and values, in bytes,
+	// split evenly between the shards. Zero means no limit.
+	MaxSize int64
+	// Shards is the number of independently locked shards.
+	Shards int
+	// Tick is the resolution of the expiry wheel: expired entries are
+	// removed within a tick, and never served.
+	Tick time.Duration
+	// Sizer returns the size of a value, DefaultSizer if nil.
+	Sizer func(value any) int64
+}
+
+// DefaultSizer returns the length of strings and byte slices, and a fixed
+// estimate for any other value.
+func DefaultSizer(value any) int64 {
+	switch v := value.(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return defaultValueSize
+	}
+}
+
+// MemoryStore is an in-memory store with deterministic eviction: every Set
+// is kept until it is evicted to make room, expires or is deleted.
+type MemoryStore struct {
+	shards []*shard
+	sizer  func(value any) int64
+	tick   time.Duration
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewMemory creates an in-memory store and starts expiring its entries in
+// the background.
+func NewMemory(opts Options) *MemoryStore {
+	if opts.Shards <= 0 {
+		opts.Shards = defaultShards
+	}
+	if opts.Tick <= 0 {
+		opts.Tick = defaultTick
+	}
+	if opts.Sizer == nil {
+		opts.Sizer = DefaultSizer
+	}
+
+	var capacity int64
+	if opts.MaxSize > 0 {
+		capacity = max(opts.MaxSize/int64(opts.Shards), 1)
+	}
+	now := time.Now().UnixNano() / int64(opts.Tick)
+	s := &MemoryStore{
+		shards: make([]*shard, opts.Shards),
+		sizer:  opts.Sizer,
+		tick:   opts.Tick,
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for i := range s.shards {
+		s.shards[i] = newShard(opts.Policy, capacity, now)
+	}
+	go s.expireLoop()
+	return s
+}
+
+// Get returns data stored from a given key.
+func (s *MemoryStore) Get(ctx context.Context, key any) (any, error) {
+	value, _, err := s.GetWithTTL(ctx, key)
+	return value, err
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL.
+func (s *MemoryStore) GetWithTTL(_ context.Context, key any) (any, time.Duration, error) {
+	k := keyString(key)
+	sh := s.shard(k)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	e, ok := sh.items[k]
+	if !ok {
+		return nil, 0, store.ErrKeyNotFound
+	}
+	var ttl time.Duration
+	if e.expiresAt > 0 {
+		if ttl = time.Until(time.Unix(0, e.expiresAt)); ttl <= 0 {
+			// Expired, waiting for its slot of the wheel.
+			return nil, 0, store.ErrKeyNotFound
+		}
+	}
+	sh.evictor.touch(e)
+	return e.value, ttl, nil
+}
+
+// Set defines data in memory for given key identifier.
+func (s *MemoryStore) Set(ctx context.Context, key any, value any) error {
+	return s.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL defines data in memory for given key identifier with TTL. A
+// ttl of 0 means no expiration.
+func (s *MemoryStore) SetWithTTL(_ context.Context, key any, value any, ttl time.Duration) error {
+	k := keyString(key)
+	e := &entry{key: k, value: value, size: int64(len(k)) + s.sizer(value), slot: -1}
+	if ttl > 0 {
+		e.expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	sh := s.shard(k)
+	if sh.capacity > 0 && e.size > sh.capacity {
+		return fmt.Errorf("%w: key '%v' needs %d bytes", ErrTooLarge, key, e.size)
+	}
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if old, ok := sh.items[k]; ok {
+		sh.remove(old)
+	}
+	for sh.capacity > 0 && sh.size+e.size > sh.capacity {
+		sh.remove(sh.evictor.victim())
+	}
+	sh.add(e, s.tick)
+	return nil
+}
+
+// Del removes data in memory for given key identifier.
+func (s *MemoryStore) Del(_ context.Context, key any) error {
+	k := keyString(key)
+	sh := s.shard(k)
+
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+
+	if e, ok := sh.items[k]; ok {
+		sh.remove(e)
+	}
+	return nil
+}
+
+// Clear resets all data in the store.
+func (s *MemoryStore) Clear(_ context.Context) error {
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		sh.reset()
+		sh.mu.Unlock()
+	}
+	return nil
+}
+
+// Wait waits for all operations to complete. Operations are applied
+// synchronously, so there is nothing to wait for.
+func (s *MemoryStore) Wait(_ context.Context) {}
+
+// Len returns the number of stored entries, expired entries not removed yet
+// included.
+func (s *MemoryStore) Len() int {
+	var n int
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		n += len(sh.items)
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// Size returns the total size of the stored keys and values.
+func (s *MemoryStore) Size() int64 {
+	var size int64
+	for _, sh := range s.shards {
+		sh.mu.Lock()
+		size += sh.size
+		sh.mu.Unlock()
+	}
+	return size
+}
+
+// Close stops expiring entries in the background.
+func (s *MemoryStore) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+// shard returns the shard holding a key.
+func (s *MemoryStore) shard(k string) *shard {
+	return s.shards[xxhash.Sum64String(k)%uint64(len(s.shards))]
+}
+
+// expireLoop advances the expiry wheel of every shard until the store is
+// closed.
+func (s *MemoryStore) expireLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.tick)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			for _, sh := range s.shards {
+				sh.mu.Lock()
+				sh.expire(now.UnixNano(), s.tick)
+				sh.mu.Unlock()
+			}
+		}
+	}
+}
+
+// keyString returns the stored form of a key.
+func keyString(key any) string {
+	switch k := key.(type) {
+	case string:
+		return k
+	case []byte:
+		return string(k)
+	default:
+		return fmt.Sprint(key)
+	}
+}
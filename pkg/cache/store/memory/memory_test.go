@@ -0,0 +1,104 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"cacheserver/pkg/cache/store"
+)
+
+func TestMemoryStoreEviction(t *testing.T) {
+	// Every entry is a one-byte key and a one-byte value, so that the
+	// single shard holds three of them.
+	tests := []struct {
+		name   string
+		policy Policy
+		// reads are the keys read between the writes of a, b, c and d.
+		reads []string
+		kept  []string
+		lost  string
+	}{
+		{"lru evicts the oldest", LRU, nil, []string{"b", "c", "d"}, "a"},
+		{"lru keeps a read key", LRU, []string{"a"}, []string{"a", "c", "d"}, "b"},
+		{"lfu evicts the least read", LFU, []string{"a", "a", "b"}, []string{"a", "b", "d"}, "c"},
+		{"lfu breaks ties by recency", LFU, []string{"c", "a", "b"}, []string{"a", "b", "d"}, "c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewMemory(Options{Policy: tt.policy, MaxSize: 6, Shards: 1})
+			defer s.Close()
+			ctx := context.Background()
+
+			for _, key := range []string{"a", "b", "c"} {
+				if err := s.Set(ctx, key, "v"); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for _, key := range tt.reads {
+				if _, err := s.Get(ctx, key); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Set(ctx, "d", "v"); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, key := range tt.kept {
+				if _, err := s.Get(ctx, key); err != nil {
+					t.Errorf("Get(%q): %v", key, err)
+				}
+			}
+			if _, err := s.Get(ctx, tt.lost); !errors.Is(err, store.ErrKeyNotFound) {
+				t.Errorf("Get(%q) = %v, want evicted", tt.lost, err)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSize(t *testing.T) {
+	s := NewMemory(Options{MaxSize: 16, Shards: 1, Tick: time.Millisecond})
+	defer s.Close()
+	ctx := context.Background()
+
+	steps := []struct {
+		name string
+		op   func() error
+		len  int
+		size int64
+	}{
+		{"set", func() error { return s.Set(ctx, "key", "value") }, 1, 8},
+		{"set bytes", func() error { return s.Set(ctx, "k", []byte("vv")) }, 2, 11},
+		{"overwrite", func() error { return s.Set(ctx, "key", "v") }, 2, 7},
+		{"evict to fit", func() error { return s.Set(ctx, "other", "0123456789") }, 1, 15},
+		{"too large", func() error {
+			if err := s.Set(ctx, "large", "0123456789abcdef"); !errors.Is(err, ErrTooLarge) {
+				return errors.New("value larger than the store accepted")
+			}
+			return nil
+		}, 1, 15},
+		{"del", func() error { return s.Del(ctx, "other") }, 0, 0},
+		{"expire", func() error {
+			if err := s.SetWithTTL(ctx, "key", "v", time.Millisecond); err != nil {
+				return err
+			}
+			deadline := time.Now().Add(5 * time.Second)
+			for s.Len() > 0 && time.Now().Before(deadline) {
+				time.Sleep(time.Millisecond)
+			}
+			return nil
+		}, 0, 0},
+	}
+	for _, step := range steps {
+		if err := step.op(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := s.Len(); got != step.len {
+			t.Fatalf("%s: Len() = %d, want %d", step.name, got, step.len)
+		}
+		if got := s.Size(); got != step.size {
+			t.Fatalf("%s: Size() = %d, want %d", step.name, got, step.size)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package memory
+
+import (
+	"container/heap"
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is a stored value.
+type entry struct {
+	key       string
+	value     any
+	size      int64
+	expiresAt int64 // unix nanoseconds, 0 for none
+	slot      int   // slot of the expiry wheel, -1 for none
+
+	elem  *list.Element // LRU position
+	freq  uint64        // LFU access count
+	seq   uint64        // LFU last access, to break ties
+	index int           // LFU heap position
+}
+
+// shard is an independently locked part of a MemoryStore.
+type shard struct {
+	mu       sync.Mutex
+	items    map[string]*entry
+	size     int64
+	capacity int64 // 0 for no limit
+	evictor  evictor
+	wheel    [wheelSlots]map[*entry]struct{}
+	cursor   int64 // last tick the wheel was advanced to
+}
+
+// newShard creates an empty shard whose wheel starts at tick now.
+func newShard(policy Policy, capacity, now int64) *shard {
+	sh := &shard{capacity: capacity, cursor: now}
+	if policy == LFU {
+		sh.evictor = &lfu{}
+	} else {
+		sh.evictor = &lru{order: list.New()}
+	}
+	sh.reset()
+	return sh
+}
+
+// add stores an entry. Callers hold sh.mu.
+func (sh *shard) add(e *entry, tick time.Duration) {
+	sh.items[e.key] = e
+	sh.size += e.size
+	sh.evictor.add(e)
+	if e.expiresAt > 0 {
+		// The slot after the one the expiry falls in, which is only
+		// advanced to once the entry has expired.
+		e.slot = int((e.expiresAt/int64(tick) + 1) % wheelSlots)
+		sh.wheel[e.slot][e] = struct{}{}
+	}
+}
+
+// remove deletes an entry. Callers hold sh.mu.
+func (sh *shard) remove(e *entry) {
+	delete(sh.items, e.key)
+	sh.size -= e.size
+	sh.evictor.remove(e)
+	if e.slot >= 0 {
+		delete(sh.wheel[e.slot], e)
+	}
+}
+
+// reset empties the shard. Callers hold sh.mu.
+func (sh *shard) reset() {
+	sh.items = make(map[string]*entry)
+	sh.size = 0
+	sh.evictor.reset()
+	for i := range sh.wheel {
+		sh.wheel[i] = make(map[*entry]struct{})
+	}
+}
+
+// expire advances the wheel to now, removing the expired entries of the
+// slots it passes. Callers hold sh.mu.
+func (sh *shard) expire(now int64, tick time.Duration) {
+	target := now / int64(tick)
+	from := max(sh.cursor+1, target-wheelSlots+1)
+	for t := from; t <= target; t++ {
+		for e := range sh.wheel[t%wheelSlots] {
+			if e.expiresAt <= now {
+				sh.remove(e)
+			}
+		}
+	}
+	sh.cursor = max(sh.cursor, target)
+}
+
+// evictor tracks the entries of a shard to pick the next one to evict.
+type evictor interface {
+	add(e *entry)
+	touch(e *entry)
+	remove(e *entry)
+	victim() *entry
+	reset()
+}
+
+// lru evicts the least recently used entry.
+type lru struct {
+	order *list.List // most recently used first
+}
+
+func (l *lru) add(e *entry)    { e.elem = l.order.PushFront(e) }
+func (l *lru) touch(e *entry)  { l.order.MoveToFront(e.elem) }
+func (l *lru) remove(e *entry) { l.order.Remove(e.elem) }
+func (l *lru) victim() *entry  { return l.order.Back().Value.(*entry) }
+func (l *lru) reset()          { l.order.Init() }
+
+// lfu evicts the least frequently used entry, with a min-heap on the access
+// count and then the last access.
+type lfu struct {
+	entries []*entry
+	clock   uint64
+}
+
+func (l *lfu) add(e *entry) {
+	l.clock++
+	e.freq, e.seq = 1, l.clock
+	heap.Push(l, e)
+}
+
+func (l *lfu) touch(e *entry) {
+	l.clock++
+	e.freq++
+	e.seq = l.clock
+	heap.Fix(l, e.index)
+}
+
+func (l *lfu) remove(e *entry) { heap.Remove(l, e.index) }
+func (l *lfu) victim() *entry  { return l.entries[0] }
+func (l *lfu) reset()          { l.entries, l.clock = nil, 0 }
+
+// Len implements heap.Interface.
+func (l *lfu) Len() int { return len(l.entries) }
+
+// Less implements heap.Interface.
+func (l *lfu) Less(i, j int) bool {
+	a, b := l.entries[i], l.entries[j]
+	if a.freq != b.freq {
+		return a.freq < b.freq
+	}
+	return a.seq < b.seq
+}
+
+// Swap implements heap.Interface.
+func (l *lfu) Swap(i, j int) {
+	l.entries[i], l.entries[j] = l.entries[j], l.entries[i]
+	l.entries[i].index = i
+	l.entries[j].index = j
+}
+
+// Push implements heap.Interface.
+func (l *lfu) Push(x any) {
+	e := x.(*entry)
+	e.index = len(l.entries)
+	l.entries = append(l.entries, e)
+}
+
+// Pop implements heap.Interface.
+func (l *lfu) Pop() any {
+	n := len(l.entries) - 1
+	e := l.entries[n]
+	l.entries[n] = nil
+	l.entries = l.entries[:n]
+	return e
+}
@@ -3,6 +3,7 @@ package redis
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -10,21 +11,45 @@ import (
 	"cacheserver/pkg/cache/store"
 )
 
-// RedisStore is a store for Redis.
+// ErrKeyType is returned for a key that is not a string.
+var ErrKeyType = errors.New("redis keys must be strings")
+
+// keyString returns key as a string, or ErrKeyType.
+func keyString(key any) (string, error) {
+	k, ok := key.(string)
+	if !ok {
+		return "", fmt.Errorf("%w, got %T", ErrKeyType, key)
+	}
+	return k, nil
+}
+
+// RedisStore is a store for Redis, over a standalone, Sentinel-managed or
+// cluster client.
 type RedisStore struct {
-	client *redis.Client
+	client   redis.UniversalClient
+	replicas redis.UniversalClient // nil when reads go to the primary
+	fresh    func(key string) bool
+	marks    *writeMarks
 }
 
 // NewRedis creates a new store to Redis instance(s).
-func NewRedis(client *redis.Client) *RedisStore {
-	return &RedisStore{
+func NewRedis(client redis.UniversalClient, opts ...Option) *RedisStore {
+	s := &RedisStore{
 		client: client,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Get returns data stored from a given key.
 func (s *RedisStore) Get(ctx context.Context, key any) (any, error) {
-	obj, err := s.client.Get(ctx, key.(string)).Result()
+	k, err := keyString(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.reader(k).Get(ctx, k).Result()
 	if errors.Is(err, redis.Nil) {
 		return nil, store.ErrKeyNotFound
 	}
@@ -33,7 +58,12 @@ func (s *RedisStore) Get(ctx context.Context, key any) (any, error) {
 
 // GetWithTTL returns data stored from a given key and its corresponding TTL.
 func (s *RedisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
-	obj, err := s.client.Get(ctx, key.(string)).Result()
+	k, err := keyString(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	reader := s.reader(k)
+	obj, err := reader.Get(ctx, k).Result()
 	if errors.Is(err, redis.Nil) {
 		return nil, 0, store.ErrKeyNotFound
 	}
@@ -41,7 +71,7 @@ func (s *RedisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duratio
 		return nil, 0, err
 	}
 
-	ttl, err := s.client.TTL(ctx, key.(string)).Result()
+	ttl, err := reader.TTL(ctx, k).Result()
 	if err != nil {
 		return nil, 0, err
 	}
@@ -51,22 +81,93 @@ func (s *RedisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duratio
 
 // Set defines data in Redis for given key identifier.
 func (s *RedisStore) Set(ctx context.Context, key any, value any) error {
-	return s.client.Set(ctx, key.(string), value, 0).Err()
+	k, err := keyString(key)
+	if err != nil {
+		return err
+	}
+	s.MarkWritten(k)
+	return s.client.Set(ctx, k, value, 0).Err()
 }
 
 // SetWithTTL defines data in Redis for given key identifier with TTL.
 func (s *RedisStore) SetWithTTL(ctx context.Context, key any, value any, ttl time.Duration) error {
-	return s.client.Set(ctx, key.(string), value, ttl).Err()
+	k, err := keyString(key)
+	if err != nil {
+		return err
+	}
+	s.MarkWritten(k)
+	return s.client.Set(ctx, k, value, ttl).Err()
+}
+
+// MGet returns the values of keys, in order, with nil for missing keys.
+func (s *RedisStore) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	reader := s.reader(keys...)
+	if _, ok := reader.(*redis.ClusterClient); !ok {
+		return reader.MGet(ctx, keys...).Result()
+	}
+
+	// Keys may span several slots, which MGET rejects; the cluster pipeline
+	// sends the GETs of each node together instead.
+	cmds := make([]*redis.StringCmd, len(keys))
+	reader.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = pipe.Get(ctx, key)
+		}
+		return nil
+	})
+	values := make([]any, len(keys))
+	for i, cmd := range cmds {
+		switch err := cmd.Err(); {
+		case err == nil:
+			values[i] = cmd.Val()
+		case !errors.Is(err, redis.Nil):
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// MSet defines data for several keys in a single pipeline. A ttl of 0
+// means no expiration.
+func (s *RedisStore) MSet(ctx context.Context, values map[string]any, ttl time.Duration) error {
+	if len(values) == 0 {
+		return nil
+	}
+	for key := range values {
+		s.MarkWritten(key)
+	}
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, value := range values {
+			pipe.Set(ctx, key, value, ttl)
+		}
+		return nil
+	})
+	return err
 }
 
 // Del removes data from Redis for given key identifier.
 func (s *RedisStore) Del(ctx context.Context, key any) error {
-	_, err := s.client.Del(ctx, key.(string)).Result()
-	return err
+	k, err := keyString(key)
+	if err != nil {
+		return err
+	}
+	s.MarkWritten(k)
+	return s.client.Del(ctx, k).Err()
 }
 
 // Clear resets all data in the store.
 func (s *RedisStore) Clear(ctx context.Context) error {
+	if s.marks != nil {
+		s.marks.markAll()
+	}
+	if cluster, ok := s.client.(*redis.ClusterClient); ok {
+		return cluster.ForEachMaster(ctx, func(ctx context.Context, client *redis.Client) error {
+			return client.FlushAll(ctx).Err()
+		})
+	}
 	return s.client.FlushAll(ctx).Err()
 }
 
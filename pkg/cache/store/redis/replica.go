@@ -0,0 +1,117 @@
+package redis
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// defaultWriteMarker is how long reads of a key go to the primary after
+	// it was written, by default.
+	defaultWriteMarker = time.Second
+	// minWriteMarkSweep is the number of marked keys below which expired
+	// marks are not swept.
+	minWriteMarkSweep = 1024
+)
+
+// Option configures a RedisStore.
+type Option func(*RedisStore)
+
+// WithReplicaReads sends Get, GetWithTTL and MGet to reader, a client
+// routing reads to replicas, while writes go to the primary. Reads of a key
+// written through the store within writeMarker (1s if not positive) still
+// go to the primary, so that a caller reads its own writes despite the
+// replication lag.
+func WithReplicaReads(reader redis.UniversalClient, writeMarker time.Duration) Option {
+	return func(s *RedisStore) {
+		if writeMarker <= 0 {
+			writeMarker = defaultWriteMarker
+		}
+		s.replicas = reader
+		s.marks = newWriteMarks(writeMarker)
+	}
+}
+
+// WithFreshReads always reads the keys matching fresh from the primary.
+func WithFreshReads(fresh func(key string) bool) Option {
+	return func(s *RedisStore) {
+		s.fresh = fresh
+	}
+}
+
+// MarkWritten records that key was written outside the store, so that it is
+// read from the primary for a while. It does nothing without replica reads.
+// Marks are local to the process: writes of other processes are not marked.
+func (s *RedisStore) MarkWritten(key string) {
+	if s.marks != nil {
+		s.marks.mark(key)
+	}
+}
+
+// reader returns the client to read keys from.
+func (s *RedisStore) reader(keys ...string) redis.UniversalClient {
+	if s.replicas == nil {
+		return s.client
+	}
+	for _, key := range keys {
+		if (s.fresh != nil && s.fresh(key)) || s.marks.recent(key) {
+			return s.client
+		}
+	}
+	return s.replicas
+}
+
+// writeMarks remembers the keys written recently by this process.
+type writeMarks struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	marks   map[string]time.Time
+	cleared time.Time
+	sweepAt int
+}
+
+// newWriteMarks creates write marks lasting ttl.
+func newWriteMarks(ttl time.Duration) *writeMarks {
+	return &writeMarks{ttl: ttl, marks: make(map[string]time.Time), sweepAt: minWriteMarkSweep}
+}
+
+// mark records a write of key, sweeping expired marks as the map grows.
+func (m *writeMarks) mark(key string) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.marks[key] = now
+
+	if len(m.marks) >= m.sweepAt {
+		for key, at := range m.marks {
+			if now.Sub(at) >= m.ttl {
+				delete(m.marks, key)
+			}
+		}
+		m.sweepAt = max(2*len(m.marks), minWriteMarkSweep)
+	}
+}
+
+// markAll records a write of every key.
+func (m *writeMarks) markAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cleared = time.Now()
+	m.marks = make(map[string]time.Time)
+	m.sweepAt = minWriteMarkSweep
+}
+
+// recent reports whether key was written within the marker TTL.
+func (m *writeMarks) recent(key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if time.Since(m.cleared) < m.ttl {
+		return true
+	}
+	at, ok := m.marks[key]
+	return ok && time.Since(at) < m.ttl
+}
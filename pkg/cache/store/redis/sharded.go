@@ -0,0 +1,234 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/dgryski/go-rendezvous"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/errgroup"
+)
+
+// ErrNoNodes is returned by a sharded store without any node.
+var ErrNoNodes = errors.New("no redis node available")
+
+// ShardedRedisStore is a store spreading keys over several Redis nodes with
+// rendezvous hashing. Adding a node only moves the keys it now owns, about
+// 1/n of them, and removing one only moves the keys it owned. Moved keys are
+// not migrated: they miss on their new node, while the copies left on the
+// old one linger until they expire.
+type ShardedRedisStore struct {
+	mu    sync.RWMutex
+	nodes map[string]*RedisStore
+	ring  *rendezvous.Rendezvous
+}
+
+// NewSharded creates a new store over the given clients, by node name.
+func NewSharded(clients map[string]redis.UniversalClient) *ShardedRedisStore {
+	s := &ShardedRedisStore{nodes: make(map[string]*RedisStore, len(clients))}
+	for name, client := range clients {
+		s.nodes[name] = NewRedis(client)
+	}
+	s.rebuild()
+	return s
+}
+
+// AddNode adds or replaces a node.
+func (s *ShardedRedisStore) AddNode(name string, client redis.UniversalClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nodes[name] = NewRedis(client)
+	s.rebuild()
+}
+
+// RemoveNode removes a node and returns its client, so that the caller can
+// close it once in-flight calls are done. It returns nil for an unknown node.
+func (s *ShardedRedisStore) RemoveNode(name string) redis.UniversalClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	node, ok := s.nodes[name]
+	if !ok {
+		return nil
+	}
+	delete(s.nodes, name)
+	s.rebuild()
+	return node.client
+}
+
+// Nodes returns the names of the nodes, sorted.
+func (s *ShardedRedisStore) Nodes() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.nodes))
+	for name := range s.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// rebuild recreates the hash ring from the nodes. Callers hold s.mu.
+func (s *ShardedRedisStore) rebuild() {
+	names := make([]string, 0, len(s.nodes))
+	for name := range s.nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	s.ring = rendezvous.New(names, xxhash.Sum64String)
+}
+
+// node returns the store of the node owning key.
+func (s *ShardedRedisStore) node(key any) (*RedisStore, error) {
+	k, err := keyString(key)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	node, ok := s.nodes[s.ring.Lookup(k)]
+	if !ok {
+		return nil, ErrNoNodes
+	}
+	return node, nil
+}
+
+// Get returns data stored from a given key.
+func (s *ShardedRedisStore) Get(ctx context.Context, key any) (any, error) {
+	node, err := s.node(key)
+	if err != nil {
+		return nil, err
+	}
+	return node.Get(ctx, key)
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL.
+func (s *ShardedRedisStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	node, err := s.node(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	return node.GetWithTTL(ctx, key)
+}
+
+// Set defines data in the node owning the key.
+func (s *ShardedRedisStore) Set(ctx context.Context, key any, value any) error {
+	node, err := s.node(key)
+	if err != nil {
+		return err
+	}
+	return node.Set(ctx, key, value)
+}
+
+// SetWithTTL defines data in the node owning the key with TTL.
+func (s *ShardedRedisStore) SetWithTTL(ctx context.Context, key any, value any, ttl time.Duration) error {
+	node, err := s.node(key)
+	if err != nil {
+		return err
+	}
+	return node.SetWithTTL(ctx, key, value, ttl)
+}
+
+// Del removes data from the node owning the key.
+func (s *ShardedRedisStore) Del(ctx context.Context, key any) error {
+	node, err := s.node(key)
+	if err != nil {
+		return err
+	}
+	return node.Del(ctx, key)
+}
+
+// MGet returns the values of keys, in order, with nil for missing keys.
+// Keys are fetched with one MGET per node, in parallel.
+func (s *ShardedRedisStore) MGet(ctx context.Context, keys ...string) ([]any, error) {
+	values := make([]any, len(keys))
+	err := s.each(ctx, keys, func(ctx context.Context, node *RedisStore, indexes []int) error {
+		nodeKeys := make([]string, len(indexes))
+		for i, index := range indexes {
+			nodeKeys[i] = keys[index]
+		}
+		nodeValues, err := node.MGet(ctx, nodeKeys...)
+		if err != nil {
+			return err
+		}
+		for i, index := range indexes {
+			values[index] = nodeValues[i]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// MSet defines data for several keys with one pipeline per node, in
+// parallel. A ttl of 0 means no expiration.
+func (s *ShardedRedisStore) MSet(ctx context.Context, values map[string]any, ttl time.Duration) error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	return s.each(ctx, keys, func(ctx context.Context, node *RedisStore, indexes []int) error {
+		nodeValues := make(map[string]any, len(indexes))
+		for _, index := range indexes {
+			nodeValues[keys[index]] = values[keys[index]]
+		}
+		return node.MSet(ctx, nodeValues, ttl)
+	})
+}
+
+// each groups keys by node and calls fn for every node in parallel with the
+// indexes of its keys.
+func (s *ShardedRedisStore) each(ctx context.Context, keys []string, fn func(ctx context.Context, node *RedisStore, indexes []int) error) error {
+	groups := make(map[*RedisStore][]int)
+	s.mu.RLock()
+	for i, key := range keys {
+		node, ok := s.nodes[s.ring.Lookup(key)]
+		if !ok {
+			s.mu.RUnlock()
+			return ErrNoNodes
+		}
+		groups[node] = append(groups[node], i)
+	}
+	s.mu.RUnlock()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for node, indexes := range groups {
+		g.Go(func() error { return fn(ctx, node, indexes) })
+	}
+	return g.Wait()
+}
+
+// Clear resets all data in every node.
+func (s *ShardedRedisStore) Clear(ctx context.Context) error {
+	s.mu.RLock()
+	nodes := make([]*RedisStore, 0, len(s.nodes))
+	for _, node := range s.nodes {
+		nodes = append(nodes, node)
+	}
+	s.mu.RUnlock()
+
+	g, ctx := errgroup.WithContext(ctx)
+	for _, node := range nodes {
+		g.Go(func() error { return node.Clear(ctx) })
+	}
+	return g.Wait()
+}
+
+// Close closes the clients of every node.
+func (s *ShardedRedisStore) Close() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var errs []error
+	for _, node := range s.nodes {
+		errs = append(errs, node.client.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Wait waits for all operations to complete.
+func (s *ShardedRedisStore) Wait(_ context.Context) {}
@@ -0,0 +1,208 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestNode starts an in-memory Redis node and returns its client.
+func newTestNode(t *testing.T) (*miniredis.Miniredis, redis.UniversalClient) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+// testKeys returns n distinct keys.
+func testKeys(n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key:%d", i)
+	}
+	return keys
+}
+
+// owners returns the node owning each key.
+func owners(s *ShardedRedisStore, keys []string) map[string]string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	owned := make(map[string]string, len(keys))
+	for _, key := range keys {
+		owned[key] = s.ring.Lookup(key)
+	}
+	return owned
+}
+
+func TestShardedRedisStoreRebalance(t *testing.T) {
+	const nodes, keys = 4, 1000
+
+	clients := make(map[string]redis.UniversalClient)
+	for i := range nodes - 1 {
+		_, clients[fmt.Sprint("node", i)] = newTestNode(t)
+	}
+	s := NewSharded(clients)
+	all := testKeys(keys)
+	before := owners(s, all)
+
+	tests := []struct {
+		name   string
+		change func()
+		// moved reports whether a key may move from one owner to another.
+		moved func(from, to string) bool
+	}{
+		{
+			name: "add",
+			change: func() {
+				_, client := newTestNode(t)
+				s.AddNode("node3", client)
+			},
+			moved: func(_, to string) bool { return to == "node3" },
+		},
+		{
+			name: "remove",
+			change: func() {
+				if s.RemoveNode("node0") == nil {
+					t.Fatal("RemoveNode(node0) = nil")
+				}
+			},
+			moved: func(from, _ string) bool { return from == "node0" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.change()
+			after := owners(s, all)
+
+			var moved int
+			for _, key := range all {
+				from, to := before[key], after[key]
+				if from == to {
+					continue
+				}
+				if !tt.moved(from, to) {
+					t.Fatalf("key %s moved from %s to %s", key, from, to)
+				}
+				moved++
+			}
+			// Rendezvous hashing only moves the share of the keys of one node.
+			if moved < keys/(2*nodes) || moved > 2*keys/(nodes-1) {
+				t.Fatalf("%d keys of %d moved", moved, keys)
+			}
+			before = after
+		})
+	}
+
+	if s.RemoveNode("unknown") != nil {
+		t.Fatal("RemoveNode(unknown) returned a client")
+	}
+}
+
+func TestShardedRedisStoreMGet(t *testing.T) {
+	ctx := context.Background()
+	servers := make(map[string]*miniredis.Miniredis)
+	clients := make(map[string]redis.UniversalClient)
+	for i := range 3 {
+		name := fmt.Sprint("node", i)
+		servers[name], clients[name] = newTestNode(t)
+	}
+	s := NewSharded(clients)
+
+	keys := testKeys(30)
+	values := make(map[string]any, len(keys))
+	for _, key := range keys {
+		values[key] = "value of " + key
+	}
+	if err := s.MSet(ctx, values, 0); err != nil {
+		t.Fatal(err)
+	}
+	// Every key is stored on its owner only.
+	for key, owner := range owners(s, keys) {
+		for name, server := range servers {
+			if server.Exists(key) != (name == owner) {
+				t.Fatalf("key %s on %s: %v, owner %s", key, name, server.Exists(key), owner)
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"none", nil},
+		{"single", keys[:1]},
+		{"every node", keys},
+		{"with missing", []string{keys[3], "missing:a", keys[7], "missing:b", keys[1]}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counts := make(map[string]int, len(servers))
+			for name, server := range servers {
+				counts[name] = server.CommandCount()
+			}
+
+			got, err := s.MGet(ctx, tt.keys...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.keys) {
+				t.Fatalf("MGet returned %d values for %d keys", len(got), len(tt.keys))
+			}
+			for i, key := range tt.keys {
+				var want any
+				if value, ok := values[key]; ok {
+					want = value
+				}
+				if got[i] != want {
+					t.Fatalf("MGet()[%d] (%s) = %v, want %v", i, key, got[i], want)
+				}
+			}
+
+			// Keys are fetched with one MGET per node owning some of them.
+			owned := make(map[string]bool)
+			for _, owner := range owners(s, tt.keys) {
+				owned[owner] = true
+			}
+			for name, server := range servers {
+				want := 0
+				if owned[name] {
+					want = 1
+				}
+				if got := server.CommandCount() - counts[name]; got != want {
+					t.Fatalf("%s received %d commands, want %d", name, got, want)
+				}
+			}
+		})
+	}
+
+	empty := NewSharded(nil)
+	if _, err := empty.MGet(ctx, "key"); !errors.Is(err, ErrNoNodes) {
+		t.Fatalf("MGet without nodes = %v, want %v", err, ErrNoNodes)
+	}
+}
+
+func TestShardedRedisStoreKeyType(t *testing.T) {
+	_, client := newTestNode(t)
+	s := NewSharded(map[string]redis.UniversalClient{"a": client})
+	ctx := context.Background()
+
+	calls := map[string]func() error{
+		"Get":        func() error { _, err := s.Get(ctx, 1); return err },
+		"GetWithTTL": func() error { _, _, err := s.GetWithTTL(ctx, 1); return err },
+		"Set":        func() error { return s.Set(ctx, 1, "value") },
+		"SetWithTTL": func() error { return s.SetWithTTL(ctx, 1, "value", time.Minute) },
+		"Del":        func() error { return s.Del(ctx, 1) },
+	}
+	for name, call := range calls {
+		if err := call(); !errors.Is(err, ErrKeyType) {
+			t.Fatalf("%s with an int key = %v, want %v", name, err, ErrKeyType)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package ristretto
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// meterName is the instrumentation scope of the Ristretto metrics.
+const meterName = "cacheserver/pkg/cache/store/ristretto"
+
+// RistrettoMetricsInterface represents the metrics of a dgraph-io/ristretto
+// cache created with Metrics enabled.
+type RistrettoMetricsInterface interface {
+	Hits() uint64
+	Misses() uint64
+	KeysAdded() uint64
+	KeysEvicted() uint64
+	CostAdded() uint64
+	CostEvicted() uint64
+	SetsDropped() uint64
+	SetsRejected() uint64
+}
+
+// ObserveMetrics reports the metrics of a Ristretto cache as counters,
+// labelled with the cache name, through the global meter provider.
+func ObserveMetrics(name string, metrics RistrettoMetricsInterface) error {
+	meter := otel.Meter(meterName)
+	counters := []struct {
+		name, description string
+		value             func() uint64
+	}{
+		{"cache.ristretto.hits", "Number of reads that found the key.", metrics.Hits},
+		{"cache.ristretto.misses", "Number of reads that did not find the key.", metrics.Misses},
+		{"cache.ristretto.keys_added", "Number of keys added.", metrics.KeysAdded},
+		{"cache.ristretto.keys_evicted", "Number of keys evicted to make room.", metrics.KeysEvicted},
+		{"cache.ristretto.cost_added", "Total cost of the values added.", metrics.CostAdded},
+		{"cache.ristretto.cost_evicted", "Total cost of the values evicted.", metrics.CostEvicted},
+		{"cache.ristretto.sets_dropped", "Number of writes dropped because the buffers were full.", metrics.SetsDropped},
+		{"cache.ristretto.sets_rejected", "Number of writes rejected by the admission policy.", metrics.SetsRejected},
+	}
+
+	instruments := make([]metric.Observable, len(counters))
+	observers := make([]metric.Int64ObservableCounter, len(counters))
+	for i, c := range counters {
+		counter, err := meter.Int64ObservableCounter(c.name, metric.WithDescription(c.description))
+		if err != nil {
+			return err
+		}
+		instruments[i], observers[i] = counter, counter
+	}
+
+	attrs := metric.WithAttributes(attribute.String("cache", name))
+	// The registration lives as long as the process, like the cache itself.
+	_, err := meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		for i, c := range counters {
+			o.ObserveInt64(observers[i], int64(c.value()), attrs)
+		}
+		return nil
+	}, instruments...)
+	return err
+}
@@ -0,0 +1,85 @@
+package ristretto
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/ristretto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestObserveMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	previous := otel.GetMeterProvider()
+	otel.SetMeterProvider(provider)
+	t.Cleanup(func() {
+		otel.SetMeterProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	cache, err := ristretto.NewCache(&ristretto.Config{
+		NumCounters: 100,
+		MaxCost:     1 << 20,
+		BufferItems: 64,
+		Metrics:     true,
+		// Costs are the given ones, as in the server.
+		IgnoreInternalCost: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cache.Close()
+	if err := ObserveMetrics("test", cache.Metrics); err != nil {
+		t.Fatal(err)
+	}
+
+	cache.Set("key", "value", 5)
+	cache.Wait()
+	cache.Get("key")
+	cache.Get("key")
+	cache.Get("missing")
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	got := make(map[string]int64)
+	for _, scope := range rm.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			sum, ok := m.Data.(metricdata.Sum[int64])
+			if !ok {
+				t.Fatalf("%s is a %T, want a sum", m.Name, m.Data)
+			}
+			for _, point := range sum.DataPoints {
+				if cache, _ := point.Attributes.Value(attribute.Key("cache")); cache.AsString() == "test" {
+					got[m.Name] = point.Value
+				}
+			}
+		}
+	}
+
+	tests := []struct {
+		name string
+		want int64
+	}{
+		{"cache.ristretto.hits", 2},
+		{"cache.ristretto.misses", 1},
+		{"cache.ristretto.keys_added", 1},
+		{"cache.ristretto.cost_added", 5},
+		{"cache.ristretto.keys_evicted", 0},
+	}
+	for _, tt := range tests {
+		value, ok := got[tt.name]
+		if !ok {
+			t.Errorf("%s not reported", tt.name)
+			continue
+		}
+		if value != tt.want {
+			t.Errorf("%s = %d, want %d", tt.name, value, tt.want)
+		}
+	}
+}
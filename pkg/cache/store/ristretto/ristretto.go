@@ -18,16 +18,53 @@ type RistrettoClientInterface interface {
 	Wait()
 }
 
+// defaultCost is the cost of values of unknown types.
+const defaultCost = 64
+
+// Coster returns the cost of a value, counted against the MaxCost of the
+// Ristretto cache.
+type Coster func(value any) int64
+
+// DefaultCoster returns the length of strings and byte slices, so that the
+// MaxCost of the cache is a size in bytes, and a fixed estimate for any
+// other value.
+func DefaultCoster(value any) int64 {
+	switch v := value.(type) {
+	case []byte:
+		return int64(len(v))
+	case string:
+		return int64(len(v))
+	default:
+		return defaultCost
+	}
+}
+
+// Option configures a RistrettoStore.
+type Option func(*RistrettoStore)
+
+// WithCoster computes the cost of values with coster instead of DefaultCoster.
+func WithCoster(coster Coster) Option {
+	return func(s *RistrettoStore) {
+		s.coster = coster
+	}
+}
+
 // RistrettoStore is a store for Ristretto (memory) library.
 type RistrettoStore struct {
 	client RistrettoClientInterface
+	coster Coster
 }
 
 // NewRistretto creates a new store to Ristretto (memory) library instance.
-func NewRistretto(client RistrettoClientInterface) *RistrettoStore {
-	return &RistrettoStore{
+func NewRistretto(client RistrettoClientInterface, opts ...Option) *RistrettoStore {
+	s := &RistrettoStore{
 		client: client,
+		coster: DefaultCoster,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // Get returns data stored from a given key.
@@ -47,7 +84,7 @@ func (s *RistrettoStore) GetWithTTL(ctx context.Context, key any) (any, time.Dur
 
 // Set defines data in Ristretto memory cache for given key identifier.
 func (s *RistrettoStore) Set(_ context.Context, key any, value any) error {
-	if set := s.client.Set(key, value, 1); !set {
+	if set := s.client.Set(key, value, s.cost(value)); !set {
 		return fmt.Errorf("failed to set value for key '%v'", key)
 	}
 	return nil
@@ -55,7 +92,7 @@ func (s *RistrettoStore) Set(_ context.Context, key any, value any) error {
 
 // SetWithTTL defines data in Ristretto memory cache with TTL.
 func (s *RistrettoStore) SetWithTTL(_ context.Context, key any, value any, ttl time.Duration) error {
-	if set := s.client.SetWithTTL(key, value, 1, ttl); !set {
+	if set := s.client.SetWithTTL(key, value, s.cost(value), ttl); !set {
 		return fmt.Errorf("failed to set value for key '%v'", key)
 	}
 	return nil
@@ -77,3 +114,9 @@ func (s *RistrettoStore) Clear(_ context.Context) error {
 func (s *RistrettoStore) Wait(_ context.Context) {
 	s.client.Wait()
 }
+
+// cost returns the cost of a value, at least 1 since Ristretto computes the
+// cost of values set with 0 itself.
+func (s *RistrettoStore) cost(value any) int64 {
+	return max(s.coster(value), 1)
+}
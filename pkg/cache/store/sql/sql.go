@@ -0,0 +1,193 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// defaultPurgeInterval is the default delay between purges of expired rows.
+const defaultPurgeInterval = time.Minute
+
+// Entry is a row of the key-value table.
+type Entry struct {
+	Namespace string `gorm:"primaryKey;size:191"`
+	Key       string `gorm:"primaryKey;size:512"`
+	Value     []byte
+	ExpiresAt int64 `gorm:"index"` // unix nanoseconds, 0 for none
+	Version   int64 // incremented on every write of the key
+}
+
+// TableName overrides the table name.
+func (Entry) TableName() string {
+	return "cache_entries"
+}
+
+// Options configures a SQLStore.
+type Options struct {
+	// Split returns the namespace and key of the row of a store key. By
+	// default every key is stored in the empty namespace.
+	Split func(key string) (namespace, name string)
+	// PurgeInterval is the delay between purges of expired rows.
+	PurgeInterval time.Duration
+	// OnError, if set, is called when a background purge fails.
+	OnError func(err error)
+}
+
+// SQLStore is a persistent store backed by a key-value table, through GORM.
+// Values must be strings or byte slices and are returned as byte slices.
+type SQLStore struct {
+	db   *gorm.DB
+	opts Options
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewSQL creates the key-value table if needed and starts purging its
+// expired rows in the background. The database is not closed by the store.
+func NewSQL(db *gorm.DB, opts Options) (*SQLStore, error) {
+	if opts.Split == nil {
+		opts.Split = func(key string) (string, string) { return "", key }
+	}
+	if opts.PurgeInterval <= 0 {
+		opts.PurgeInterval = defaultPurgeInterval
+	}
+	if err := db.AutoMigrate(&Entry{}); err != nil {
+		return nil, err
+	}
+
+	s := &SQLStore{
+		db:   db,
+		opts: opts,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go s.purgeLoop()
+	return s, nil
+}
+
+// Get returns data stored from a given key.
+func (s *SQLStore) Get(ctx context.Context, key any) (any, error) {
+	value, _, err := s.GetWithTTL(ctx, key)
+	return value, err
+}
+
+// GetWithTTL returns data stored from a given key and its corresponding TTL.
+func (s *SQLStore) GetWithTTL(ctx context.Context, key any) (any, time.Duration, error) {
+	var entry Entry
+	err := s.db.WithContext(ctx).Where(s.where(key)).Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, store.ErrKeyNotFound
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ttl time.Duration
+	if entry.ExpiresAt > 0 {
+		if ttl = time.Until(time.Unix(0, entry.ExpiresAt)); ttl <= 0 {
+			// Expired, waiting for the next purge.
+			return nil, 0, store.ErrKeyNotFound
+		}
+	}
+	return entry.Value, ttl, nil
+}
+
+// Set defines data in the table for given key identifier.
+func (s *SQLStore) Set(ctx context.Context, key any, value any) error {
+	return s.SetWithTTL(ctx, key, value, 0)
+}
+
+// SetWithTTL inserts or replaces the row of a key. A ttl of 0 means no
+// expiration.
+func (s *SQLStore) SetWithTTL(ctx context.Context, key any, value any, ttl time.Duration) error {
+	var data []byte
+	switch v := value.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return fmt.Errorf("unsupported value type %T for key '%v'", value, key)
+	}
+
+	entry := &Entry{Value: data, Version: 1}
+	entry.Namespace, entry.Key = s.opts.Split(key.(string))
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl).UnixNano()
+	}
+
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "namespace"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"value":      entry.Value,
+			"expires_at": entry.ExpiresAt,
+			"version":    gorm.Expr("? + 1", clause.Column{Table: entry.TableName(), Name: "version"}),
+		}),
+	}).Create(entry).Error
+}
+
+// Del removes the row of a key.
+func (s *SQLStore) Del(ctx context.Context, key any) error {
+	return s.db.WithContext(ctx).Where(s.where(key)).Delete(&Entry{}).Error
+}
+
+// Clear removes every row of the table.
+func (s *SQLStore) Clear(ctx context.Context) error {
+	return s.db.WithContext(ctx).Session(&gorm.Session{AllowGlobalUpdate: true}).Delete(&Entry{}).Error
+}
+
+// Wait waits for all operations to complete.
+func (s *SQLStore) Wait(_ context.Context) {}
+
+// Purge removes the expired rows, found through the index on expires_at.
+func (s *SQLStore) Purge(ctx context.Context) error {
+	return s.db.WithContext(ctx).
+		Where("expires_at > 0 AND expires_at <= ?", time.Now().UnixNano()).
+		Delete(&Entry{}).Error
+}
+
+// Close stops the background purge.
+func (s *SQLStore) Close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+	})
+}
+
+// purgeLoop purges expired rows until the store is closed.
+func (s *SQLStore) purgeLoop() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.opts.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), s.opts.PurgeInterval)
+			if err := s.Purge(ctx); err != nil && s.opts.OnError != nil {
+				s.opts.OnError(err)
+			}
+			cancel()
+		}
+	}
+}
+
+// where returns the condition selecting the row of a key. A map is used
+// rather than a string so that GORM quotes the key column, a reserved word
+// in MySQL.
+func (s *SQLStore) where(key any) map[string]any {
+	namespace, name := s.opts.Split(key.(string))
+	return map[string]any{"namespace": namespace, "key": name}
+}
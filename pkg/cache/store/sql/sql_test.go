@@ -0,0 +1,155 @@
+package sql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// newTestSQL returns a store over an in-memory SQLite database, splitting
+// keys on the first slash.
+func newTestSQL(t *testing.T) (*SQLStore, *gorm.DB) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to ":memory:" opens a distinct database.
+	sqlDB.SetMaxOpenConns(1)
+
+	s, err := NewSQL(db, Options{
+		Split: func(key string) (string, string) {
+			namespace, name, _ := strings.Cut(key, "/")
+			return namespace, name
+		},
+		PurgeInterval: time.Hour,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		_ = sqlDB.Close()
+	})
+	return s, db
+}
+
+// row returns the row of a key, expired or not.
+func row(t *testing.T, db *gorm.DB, namespace, key string) (Entry, bool) {
+	t.Helper()
+
+	var entry Entry
+	err := db.Where(map[string]any{"namespace": namespace, "key": key}).Take(&entry).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entry, false
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return entry, true
+}
+
+func TestSQLStoreUpsert(t *testing.T) {
+	s, db := newTestSQL(t)
+	ctx := context.Background()
+
+	steps := []struct {
+		name    string
+		value   any
+		ttl     time.Duration
+		want    string
+		version int64
+	}{
+		{"insert", "a", 0, "a", 1},
+		{"update", []byte("b"), time.Hour, "b", 2},
+		{"update without ttl", "c", 0, "c", 3},
+	}
+	for _, step := range steps {
+		if err := s.SetWithTTL(ctx, "ns/key", step.value, step.ttl); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		entry, ok := row(t, db, "ns", "key")
+		if !ok {
+			t.Fatalf("%s: row not found", step.name)
+		}
+		if entry.Version != step.version {
+			t.Fatalf("%s: version = %d, want %d", step.name, entry.Version, step.version)
+		}
+
+		value, ttl, err := s.GetWithTTL(ctx, "ns/key")
+		if err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if got := string(value.([]byte)); got != step.want {
+			t.Fatalf("%s: value = %q, want %q", step.name, got, step.want)
+		}
+		if ttl > step.ttl || ttl < step.ttl-time.Second {
+			t.Fatalf("%s: ttl = %v, want %v", step.name, ttl, step.ttl)
+		}
+	}
+
+	// Keys of other namespaces are other rows.
+	if err := s.Set(ctx, "other/key", "x"); err != nil {
+		t.Fatal(err)
+	}
+	if entry, _ := row(t, db, "other", "key"); entry.Version != 1 {
+		t.Fatalf("version in another namespace = %d, want 1", entry.Version)
+	}
+	if err := s.SetWithTTL(ctx, "ns/key", 42, 0); err == nil {
+		t.Fatal("unsupported value type accepted")
+	}
+}
+
+func TestSQLStoreExpiry(t *testing.T) {
+	s, db := newTestSQL(t)
+	ctx := context.Background()
+
+	keys := []struct {
+		key     string
+		ttl     time.Duration
+		expired bool
+	}{
+		{"ns/expired", time.Millisecond, true},
+		{"ns/other expired", time.Millisecond, true},
+		{"ns/live", time.Hour, false},
+		{"ns/persistent", 0, false},
+	}
+	for _, k := range keys {
+		if err := s.SetWithTTL(ctx, k.key, "v", k.ttl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	for _, purged := range []bool{false, true} {
+		if purged {
+			if err := s.Purge(ctx); err != nil {
+				t.Fatal(err)
+			}
+		}
+		for _, k := range keys {
+			// Expired rows are never served, and only deleted by a purge.
+			_, err := s.Get(ctx, k.key)
+			if k.expired != errors.Is(err, store.ErrKeyNotFound) {
+				t.Fatalf("Get(%s) after purge %v = %v, expired %v", k.key, purged, err, k.expired)
+			}
+			namespace, name, _ := strings.Cut(k.key, "/")
+			if _, ok := row(t, db, namespace, name); ok != (!k.expired || !purged) {
+				t.Fatalf("row of %s after purge %v present %v", k.key, purged, ok)
+			}
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+
+	"cacheserver/pkg/cache/store"
+)
+
+// WritePolicy selects how a ChainCache propagates writes and deletes to its
+// levels. The last level is treated as the source of truth.
+type WritePolicy int
+
+const (
+	// WriteThrough writes the last level first and then the levels above it,
+	// bottom up. A failure of the last level fails the write before any
+	// cache is touched; a level above it that fails is invalidated so that
+	// it cannot keep serving the previous value.
+	WriteThrough WritePolicy = iota
+	// WriteBehind writes the levels above the last one and records the write
+	// of the last level in a durable journal, flushed in batches. Until then
+	// the pending write is read instead of the last level. Requires
+	// WithWriteBehind.
+	WriteBehind
+	// WriteAround writes the last level only and invalidates the levels
+	// above it, which are repopulated on the next read.
+	WriteAround
+)
+
+const (
+	// defaultWriteBehindBatchSize is the default number of writes flushed at once.
+	defaultWriteBehindBatchSize = 100
+	// defaultWriteBehindInterval is the default delay between flushes.
+	defaultWriteBehindInterval = time.Second
+)
+
+// WriteBehindConfig configures the flushing of a write-behind chain.
+type WriteBehindConfig struct {
+	// BatchSize is the maximum number of writes applied per flush.
+	BatchSize int
+	// FlushInterval is the delay between flushes.
+	FlushInterval time.Duration
+}
+
+// WithWritePolicy selects the write policy of the chain. WriteBehind is
+// ignored unless a journal is given with WithWriteBehind.
+func WithWritePolicy[T any](policy WritePolicy) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		c.writePolicy = policy
+	}
+}
+
+// WithWriteBehind selects the WriteBehind policy, queueing writes of the
+// last level in journal. Values are stored in the journal as JSON.
+func WithWriteBehind[T any](journal *Journal, cfg WriteBehindConfig) ChainOption[T] {
+	return func(c *ChainCache[T]) {
+		if cfg.BatchSize <= 0 {
+			cfg.BatchSize = defaultWriteBehindBatchSize
+		}
+		if cfg.FlushInterval <= 0 {
+			cfg.FlushInterval = defaultWriteBehindInterval
+		}
+		c.writePolicy = WriteBehind
+		c.journal = journal
+		c.writeBehind = cfg
+	}
+}
+
+// write stores a value according to the write policy.
+func (c *ChainCache[T]) write(ctx context.Context, key any, obj T, ttl time.Duration) error {
+	if len(c.caches) == 0 {
+		return nil
+	}
+	defer c.stamp(key)()
+	last := len(c.caches) - 1
+
+	var errs []error
+	switch c.writePolicy {
+	case WriteBehind:
+		if err := c.queue(key, obj, ttl); err != nil {
+			return err
+		}
+		errs = c.writeLevels(ctx, key, obj, ttl, last)
+	case WriteAround:
+		if err := c.setLevel(ctx, last, key, obj, ttl); err != nil {
+			return fmt.Errorf("unable to set item into cache: %w", err)
+		}
+		c.forget(key)
+		errs = c.invalidate(ctx, key, last)
+	default:
+		if err := c.setLevel(ctx, last, key, obj, ttl); err != nil {
+			return fmt.Errorf("unable to set item into cache: %w", err)
+		}
+		errs = c.writeLevels(ctx, key, obj, ttl, last)
+	}
+	if c.writePolicy != WriteAround {
+		c.markFresh(key, ttl)
+	}
+	return errors.Join(errs...)
+}
+
+// SetSource stores a value in the last level only, following the write
+// policy: it is queued in the journal of a write-behind chain. It persists
+// values written to the levels above by other means, which are left as is.
+// A ttl of 0 means no expiration.
+func (c *ChainCache[T]) SetSource(ctx context.Context, key any, obj T, ttl time.Duration) error {
+	if len(c.caches) == 0 {
+		return nil
+	}
+	defer c.stamp(key)()
+	if c.writePolicy == WriteBehind {
+		return c.queue(key, obj, ttl)
+	}
+	if err := c.setLevel(ctx, len(c.caches)-1, key, obj, ttl); err != nil {
+		return fmt.Errorf("unable to set item into cache: %w", err)
+	}
+	return nil
+}
+
+// DelSource deletes a value from the last level only, following the write
+// policy, leaving the levels above as is.
+func (c *ChainCache[T]) DelSource(ctx context.Context, key any) error {
+	if len(c.caches) == 0 {
+		return nil
+	}
+	defer c.stamp(key)()
+	if c.writePolicy == WriteBehind {
+		if err := c.journal.Append(journalOpDel, keyFunc(key), nil, 0); err != nil {
+			return fmt.Errorf("unable to queue delete for the last cache: %w", err)
+		}
+		return nil
+	}
+	if err := c.caches[len(c.caches)-1].Del(ctx, key); err != nil {
+		return fmt.Errorf("unable to delete item from cache: %w", err)
+	}
+	return nil
+}
+
+// queue records the write of a value to the last level in the journal.
+func (c *ChainCache[T]) queue(key any, obj T, ttl time.Duration) error {
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return err
+	}
+	var expiresAt int64
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl).UnixNano()
+	}
+	if err := c.journal.Append(journalOpSet, keyFunc(key), data, expiresAt); err != nil {
+		return fmt.Errorf("unable to queue item for the last cache: %w", err)
+	}
+	return nil
+}
+
+// writeLevels writes a value into the levels above until, bottom up,
+// invalidating any level that fails. It returns the invalidation failures,
+// which leave a level serving an outdated value.
+func (c *ChainCache[T]) writeLevels(ctx context.Context, key any, obj T, ttl time.Duration, until int) []error {
+	var errs []error
+	for i := until - 1; i >= 0; i-- {
+		if err := c.setLevel(ctx, i, key, obj, ttl); err != nil {
+			if delErr := c.caches[i].Del(ctx, key); delErr != nil {
+				errs = append(errs, fmt.Errorf("unable to set item into cache: %w", errors.Join(err, delErr)))
+			}
+		}
+	}
+	return errs
+}
+
+// stamp advances the generation of a key before and, through the returned
+// function, after it is written or deleted, so that any value read while
+// the levels change is considered outdated.
+func (c *ChainCache[T]) stamp(key any) func() {
+	k := keyFunc(key)
+	c.gens.advance(k)
+	return func() { c.gens.advance(k) }
+}
+
+// setLevel stores a value in level i.
+func (c *ChainCache[T]) setLevel(ctx context.Context, i int, key any, obj T, ttl time.Duration) error {
+	if ttl > 0 {
+		return c.caches[i].SetWithTTL(ctx, key, obj, c.levelTTL(i, ttl))
+	}
+	return c.caches[i].Set(ctx, key, obj)
+}
+
+// remove deletes a value according to the write policy.
+func (c *ChainCache[T]) remove(ctx context.Context, key any) error {
+	c.forget(key)
+	if len(c.caches) == 0 {
+		return nil
+	}
+	defer c.stamp(key)()
+	last := len(c.caches) - 1
+
+	// The levels above are invalidated even if the last one fails, so that
+	// they do not keep serving the deleted value.
+	var errs []error
+	if c.writePolicy == WriteBehind {
+		if err := c.journal.Append(journalOpDel, keyFunc(key), nil, 0); err != nil {
+			errs = append(errs, fmt.Errorf("unable to queue delete for the last cache: %w", err))
+		}
+	} else if err := c.caches[last].Del(ctx, key); err != nil {
+		errs = append(errs, fmt.Errorf("unable to delete item from cache: %w", err))
+	}
+	return errors.Join(append(errs, c.invalidate(ctx, key, last)...)...)
+}
+
+// Invalidate deletes a key changed in level by other means than the chain
+// from the levels above it. Like a write, it makes the values of the key
+// read before outdated, so that pending backfills and refreshes cannot copy
+// the previous value back into those levels.
+func (c *ChainCache[T]) Invalidate(ctx context.Context, key any, level int) error {
+	c.forget(key)
+	defer c.stamp(key)()
+	return errors.Join(c.invalidate(ctx, key, min(max(level, 0), len(c.caches)))...)
+}
+
+// invalidate deletes a key from the levels above until.
+func (c *ChainCache[T]) invalidate(ctx context.Context, key any, until int) []error {
+	var errs []error
+	for _, cache := range c.caches[:until] {
+		if err := cache.Del(ctx, key); err != nil {
+			errs = append(errs, fmt.Errorf("unable to invalidate item in cache: %w", err))
+		}
+	}
+	return errs
+}
+
+// flushLoop periodically applies the journal to the last level until the
+// chain is closed, then flushes once more.
+func (c *ChainCache[T]) flushLoop() {
+	defer close(c.flushDone)
+
+	ticker := time.NewTicker(c.writeBehind.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.stopFlush:
+			ctx, cancel := context.WithTimeout(context.Background(), refreshTimeout)
+			c.flush(ctx)
+			cancel()
+			return
+		case <-ticker.C:
+			c.flush(context.Background())
+		}
+	}
+}
+
+// flush applies pending writes to the last level in batches, stopping at
+// the first failure so that it is retried on the next flush.
+func (c *ChainCache[T]) flush(ctx context.Context) error {
+	last := c.caches[len(c.caches)-1]
+	for ctx.Err() == nil {
+		entries := c.journal.batch(c.writeBehind.BatchSize)
+		if len(entries) == 0 {
+			return nil
+		}
+
+		applied := 0
+		var err error
+		for _, entry := range entries {
+			if err = c.apply(ctx, last, entry); err != nil {
+				break
+			}
+			applied++
+		}
+		if ackErr := c.journal.ack(entries[:applied]); ackErr != nil {
+			err = errors.Join(err, ackErr)
+		}
+		c.metrics.writeBehindWrites.Add(ctx, int64(applied), metric.WithAttributes(resultAttr(nil)))
+		if err != nil {
+			c.metrics.writeBehindWrites.Add(ctx, 1, metric.WithAttributes(resultAttr(err)))
+			return err
+		}
+		if len(entries) < c.writeBehind.BatchSize {
+			return nil
+		}
+	}
+	return ctx.Err()
+}
+
+// apply performs a journaled write on the last level. Like any write, it
+// makes the values of the key read before outdated. A delete clears the
+// levels above once more, unless a newer write of the key is pending, so
+// that no copy of the deleted value outlives it there.
+func (c *ChainCache[T]) apply(ctx context.Context, last Cache[T], entry journalEntry) error {
+	defer c.stamp(entry.Key)()
+
+	obj, ttl, err := decodeEntry[T](entry)
+	switch {
+	case errors.Is(err, store.ErrKeyNotFound):
+		if err := last.Del(ctx, entry.Key); err != nil {
+			return err
+		}
+		if current, ok := c.journal.pendingEntry(entry.Key); !ok || current.Seq == entry.Seq {
+			c.invalidate(ctx, entry.Key, len(c.caches)-1)
+		}
+		return nil
+	case err != nil:
+		// A value that cannot be decoded never will be; drop it.
+		return nil
+	case ttl == 0:
+		return last.Set(ctx, entry.Key, obj)
+	default:
+		return last.SetWithTTL(ctx, entry.Key, obj, ttl)
+	}
+}
+
+// getLevel returns the value of a key in level i and its TTL. The last level
+// of a write-behind chain is not read for a key with a pending write, which
+// is authoritative: a pending delete is a miss and a pending write returns
+// the queued value.
+func (c *ChainCache[T]) getLevel(ctx context.Context, i int, key any) (T, time.Duration, error) {
+	if c.journal != nil && i == len(c.caches)-1 {
+		if entry, ok := c.journal.pendingEntry(keyFunc(key)); ok {
+			obj, ttl, err := decodeEntry[T](entry)
+			if err == nil || errors.Is(err, store.ErrKeyNotFound) {
+				return obj, ttl, err
+			}
+			// The flush drops a value it cannot decode; read the level.
+		}
+	}
+	return c.caches[i].GetWithTTL(ctx, key)
+}
+
+// decodeEntry returns the value written by a journal entry and its TTL, or
+// store.ErrKeyNotFound for a delete or a value that has already expired.
+func decodeEntry[T any](entry journalEntry) (T, time.Duration, error) {
+	var obj T
+	if entry.Op == journalOpDel {
+		return obj, 0, store.ErrKeyNotFound
+	}
+	if err := json.Unmarshal(entry.Value, &obj); err != nil {
+		return obj, 0, err
+	}
+	if entry.ExpiresAt == 0 {
+		return obj, 0, nil
+	}
+	ttl := time.Until(time.Unix(0, entry.ExpiresAt))
+	if ttl <= 0 {
+		return *new(T), 0, store.ErrKeyNotFound
+	}
+	return obj, ttl, nil
+}